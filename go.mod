@@ -6,10 +6,11 @@ require (
 	github.com/stretchr/testify v1.11.1
 	github.com/tilotech/tilores-plugin-api v0.21.1
 	golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39
+	golang.org/x/text v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
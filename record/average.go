@@ -1,20 +1,26 @@
 package record
 
 import (
+	"fmt"
+	"math/big"
+
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
 // Average returns the average value of the provided numeric path.
 //
+// The values are added using compensated summation to avoid rounding drift
+// when averaging many values.
+//
 // Using average on non-numeric paths will raise an error.
 // Null values are ignored in the calculation.
 // Returns null if all values are null.
 func Average(records []*api.Record, path string) (*float64, error) {
-	sum := 0.0
+	sum := compensatedSum{}
 	counted := 0.0
 	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
 		if number != nil {
-			sum += *number
+			sum.add(*number)
 			counted++
 		}
 		return nil
@@ -25,5 +31,31 @@ func Average(records []*api.Record, path string) (*float64, error) {
 	if counted == 0 {
 		return nil, nil
 	}
-	return pointer(sum / counted), nil
+	return pointer(sum.value() / counted), nil
+}
+
+// AverageDecimal returns the exact average value of the provided numeric path
+// as a decimal string.
+//
+// The values are parsed into arbitrary-precision decimals and the result is
+// rounded to the provided number of decimal places, with halves rounded away
+// from zero.
+//
+// Using averageDecimal on non-numeric paths or with a negative precision will
+// raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func AverageDecimal(records []*api.Record, path string, precision int) (*string, error) {
+	if precision < 0 {
+		return nil, fmt.Errorf("invalid precision %v, must not be negative", precision)
+	}
+	sum, _, counted, err := sumDecimal(records, path)
+	if err != nil {
+		return nil, err
+	}
+	if counted == 0 {
+		return nil, nil
+	}
+	average := sum.Quo(sum, new(big.Rat).SetInt64(int64(counted)))
+	return pointer(formatDecimal(average, precision)), nil
 }
@@ -1,6 +1,7 @@
 package record_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,6 +81,14 @@ func TestAverage(t *testing.T) {
 			},
 			expectError: true,
 		},
+		"infinite values": {
+			records:  numRecords("-Inf", 1.0),
+			expected: pointer(math.Inf(-1)),
+		},
+		"many small values do not drift": {
+			records:  numRecords(0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1),
+			expected: pointer(0.1),
+		},
 	}
 
 	for name, c := range cases {
@@ -96,3 +105,63 @@ func TestAverage(t *testing.T) {
 		})
 	}
 }
+
+func TestAverageDecimal(t *testing.T) {
+	cases := map[string]struct {
+		records     []*api.Record
+		precision   int
+		expected    *string
+		expectError bool
+	}{
+		"empty list": {
+			records:   []*api.Record{},
+			precision: 2,
+		},
+		"list with all nil values": {
+			records:   []*api.Record{nil, {ID: "r1", Data: map[string]any{}}},
+			precision: 2,
+		},
+		"exact average": {
+			records:   numRecords(0.1, 0.2, "0.3"),
+			precision: 2,
+			expected:  pointer("0.20"),
+		},
+		"rounds to precision": {
+			records:   numRecords("1", "1", "2"),
+			precision: 4,
+			expected:  pointer("1.3333"),
+		},
+		"rounds halves away from zero": {
+			records:   numRecords("-1", "-2"),
+			precision: 0,
+			expected:  pointer("-2"),
+		},
+		"values rounded to zero have no sign": {
+			records:   numRecords("-0.5", "-0.2"),
+			precision: 0,
+			expected:  pointer("0"),
+		},
+		"negative precision causes an error": {
+			records:     numRecords("1"),
+			precision:   -1,
+			expectError: true,
+		},
+		"non numbers values causes an error": {
+			records:     numRecords("10.0", "not number"),
+			precision:   2,
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.AverageDecimal(c.records, "num", c.precision)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
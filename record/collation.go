@@ -0,0 +1,24 @@
+package record
+
+import (
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// collationCompare returns a function that compares two strings according to
+// the collation rules for the provided BCP 47 language tag, e.g. "de" or "sv".
+//
+// The tag "und" uses the Unicode default collation order without any locale
+// tailoring. An empty collation uses the byte-wise comparison.
+func collationCompare(collation string) (func(a, b string) int, error) {
+	if collation == "" {
+		return strings.Compare, nil
+	}
+	tag, err := language.Parse(collation)
+	if err != nil {
+		return nil, err
+	}
+	return collate.New(tag).CompareString, nil
+}
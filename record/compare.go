@@ -0,0 +1,70 @@
+package record
+
+import (
+	"cmp"
+	"strings"
+	"time"
+)
+
+func compareTime(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// compareNatural compares two strings while treating consecutive digits as a
+// single number.
+//
+// Numbers with the same value but a different amount of leading zeros are
+// ordered by their length, e.g. "a1" < "a01". The non-digit parts are compared
+// using compareText.
+func compareNatural(a, b string, compareText func(a, b string) int) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var numA, numB string
+			numA, a = splitDigits(a)
+			numB, b = splitDigits(b)
+			if c := compareDigits(numA, numB); c != 0 {
+				return c
+			}
+			continue
+		}
+		var textA, textB string
+		textA, a = splitNonDigits(a)
+		textB, b = splitNonDigits(b)
+		if c := compareText(textA, textB); c != 0 {
+			return c
+		}
+	}
+	return compareText(a, b)
+}
+
+func compareDigits(a, b string) int {
+	trimmedA := strings.TrimLeft(a, "0")
+	trimmedB := strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(trimmedA), len(trimmedB)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(trimmedA, trimmedB); c != 0 {
+		return c
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func splitNonDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && !isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
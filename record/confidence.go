@@ -22,6 +22,24 @@ import (
 // Null values are ignored in the calculation.
 // Returns null if all values are null.
 func Confidence(records []*api.Record, path string, caseSensitive bool) (*float64, error) {
+	frequencies, valueCount, err := collectFrequencies(records, path, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	if valueCount == 0 {
+		return nil, nil
+	}
+	probSum := 0.0
+	for _, freq := range frequencies {
+		prob := float64(freq) / float64(valueCount)
+		probSum += prob * float64(freq)
+	}
+	return pointer(probSum / float64(valueCount)), nil
+}
+
+// collectFrequencies returns how often each non-null value of the provided
+// path is present, together with the total number of non-null values.
+func collectFrequencies(records []*api.Record, path string, caseSensitive bool) (map[string]int, int, error) {
 	frequencies := make(map[string]int, len(records))
 	valueCount := 0
 	err := VisitString(records, path, caseSensitive, func(val *string, _ *api.Record) error {
@@ -31,16 +49,84 @@ func Confidence(records []*api.Record, path string, caseSensitive bool) (*float6
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return frequencies, valueCount, nil
+}
+
+// weightedConfidence is a variant of Confidence that uses the provided weight
+// for each record instead of counting each value once.
+func weightedConfidence(records []*api.Record, path string, caseSensitive bool, weight func(record *api.Record) (float64, error)) (*float64, error) {
+	values, total, err := collectWeightedValues(records, path, caseSensitive, weight)
 	if err != nil {
 		return nil, err
 	}
-	if valueCount == 0 {
+	if total == 0 {
 		return nil, nil
 	}
 	probSum := 0.0
-	for _, freq := range frequencies {
-		prob := float64(freq) / float64(valueCount)
-		probSum += prob * float64(freq)
+	for _, v := range values {
+		prob := v.weight / total
+		probSum += prob * prob
 	}
-	return pointer(probSum / float64(valueCount)), nil
+	return pointer(probSum), nil
+}
+
+// weightedValue contains the frequency and the summed up weight of a single
+// unique value.
+type weightedValue struct {
+	value     any
+	key       string
+	frequency int
+	weight    float64
+}
+
+// collectWeightedValues returns all unique non-null values of the provided
+// path in the order of their first occurrence, together with the sum of all
+// weights.
+//
+// Values are compared the same way as in ValuesDistinct. Each value counts
+// with the weight of its record. The weight function is called at most once
+// per record.
+func collectWeightedValues(records []*api.Record, path string, caseSensitive bool, weight func(record *api.Record) (float64, error)) ([]*weightedValue, float64, error) {
+	values := make([]*weightedValue, 0)
+	idx := make(map[string]*weightedValue, len(records))
+	weights := make(map[*api.Record]float64, len(records))
+	total := 0.0
+	err := Visit(records, path, func(v any, record *api.Record) error {
+		val, err := validateString(v, caseSensitive)
+		if err != nil || val == nil {
+			return err
+		}
+		w, ok := weights[record]
+		if !ok {
+			w, err = weight(record)
+			if err != nil {
+				return err
+			}
+			weights[record] = w
+		}
+		entry, ok := idx[*val]
+		if !ok {
+			entry = &weightedValue{
+				value: v,
+				key:   *val,
+			}
+			idx[*val] = entry
+			values = append(values, entry)
+		}
+		entry.frequency++
+		entry.weight += w
+		total += w
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return values, total, nil
+}
+
+func equalWeight(_ *api.Record) (float64, error) {
+	return 1, nil
 }
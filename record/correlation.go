@@ -0,0 +1,136 @@
+package record
+
+import (
+	"cmp"
+	"math"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// Covariance returns the population covariance between the two provided
+// numeric paths.
+//
+// Values are paired by record. If both paths contain a wildcard at the same
+// position, e.g. items.*.amount and items.*.discount, then the values are
+// paired by array element.
+//
+// Using covariance on non-numeric paths or paths that result in a different
+// amount of values will raise an error.
+// Pairs where either value is null are ignored in the calculation.
+// Returns null if all pairs are ignored.
+func Covariance(records []*api.Record, pathA string, pathB string) (*float64, error) {
+	a, b, err := collectNumberPairs(records, pathA, pathB)
+	if err != nil || len(a) == 0 {
+		return nil, err
+	}
+	meanA, meanB := mean(a), mean(b)
+	sum := 0.0
+	for i := range a {
+		sum += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return pointer(sum / float64(len(a))), nil
+}
+
+// PearsonCorrelation returns the Pearson correlation coefficient between the
+// two provided numeric paths, ranging from -1 to 1.
+//
+// Values are paired the same way as in Covariance.
+//
+// Using pearsonCorrelation on non-numeric paths or paths that result in a
+// different amount of values will raise an error.
+// Pairs where either value is null are ignored in the calculation.
+// Returns null if there are less than two pairs or if all values of a path
+// are equal.
+func PearsonCorrelation(records []*api.Record, pathA string, pathB string) (*float64, error) {
+	a, b, err := collectNumberPairs(records, pathA, pathB)
+	if err != nil {
+		return nil, err
+	}
+	return pearson(a, b), nil
+}
+
+// SpearmanCorrelation returns the Spearman rank correlation coefficient
+// between the two provided numeric paths, ranging from -1 to 1.
+//
+// Values are paired the same way as in Covariance. Equal values receive the
+// average of their ranks.
+//
+// Using spearmanCorrelation on non-numeric paths or paths that result in a
+// different amount of values will raise an error.
+// Pairs where either value is null are ignored in the calculation.
+// Returns null if there are less than two pairs or if all values of a path
+// are equal.
+func SpearmanCorrelation(records []*api.Record, pathA string, pathB string) (*float64, error) {
+	a, b, err := collectNumberPairs(records, pathA, pathB)
+	if err != nil {
+		return nil, err
+	}
+	return pearson(ranks(a), ranks(b)), nil
+}
+
+func collectNumberPairs(records []*api.Record, pathA string, pathB string) ([]float64, []float64, error) {
+	a := []float64{}
+	b := []float64{}
+	err := visitNumberPairs(records, pathA, pathB, func(valA, valB float64) error {
+		a = append(a, valA)
+		b = append(b, valB)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+func pearson(a, b []float64) *float64 {
+	if len(a) < 2 {
+		return nil
+	}
+	meanA, meanB := mean(a), mean(b)
+	sumAB, sumAA, sumBB := 0.0, 0.0, 0.0
+	for i := range a {
+		difA := a[i] - meanA
+		difB := b[i] - meanB
+		sumAB += difA * difB
+		sumAA += difA * difA
+		sumBB += difB * difB
+	}
+	if sumAA == 0 || sumBB == 0 {
+		return nil
+	}
+	return pointer(sumAB / math.Sqrt(sumAA*sumBB))
+}
+
+func mean(numbers []float64) float64 {
+	sum := 0.0
+	for _, n := range numbers {
+		sum += n
+	}
+	return sum / float64(len(numbers))
+}
+
+// ranks returns the rank of each number, starting with 1 for the lowest
+// number. Equal numbers receive the average of their ranks.
+func ranks(numbers []float64) []float64 {
+	idx := make([]int, len(numbers))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(i, j int) int {
+		return cmp.Compare(numbers[i], numbers[j])
+	})
+	result := make([]float64, len(numbers))
+	for start := 0; start < len(idx); {
+		end := start + 1
+		for end < len(idx) && numbers[idx[end]] == numbers[idx[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range idx[start:end] {
+			result[i] = rank
+		}
+		start = end
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package record_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestCorrelation(t *testing.T) {
+	defaultRecords := []*api.Record{
+		{ID: "r1", Data: map[string]any{"a": 1.0, "b": 2.0}},
+		{ID: "r2", Data: map[string]any{"a": "2", "b": 4.0}},
+		{ID: "r3", Data: map[string]any{"a": 3.0, "b": 5.0}},
+		{ID: "r4", Data: map[string]any{"a": 4.0, "b": 4.0}},
+		{ID: "r5", Data: map[string]any{"a": 5.0, "b": 5.0}},
+		{ID: "r6", Data: map[string]any{"a": 6.0}},
+		{ID: "r7", Data: map[string]any{"b": 6.0}},
+		nil,
+	}
+
+	cases := map[string]struct {
+		records            []*api.Record
+		pathA              string
+		pathB              string
+		expectedCovariance *float64
+		expectedPearson    *float64
+		expectedSpearman   *float64
+		expectError        bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+			pathA:   "a",
+			pathB:   "b",
+		},
+		"single pair": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": 1.0, "b": 2.0}},
+			},
+			pathA:              "a",
+			pathB:              "b",
+			expectedCovariance: pointer(0.0),
+		},
+		"different values": {
+			records:            defaultRecords,
+			pathA:              "a",
+			pathB:              "b",
+			expectedCovariance: pointer(1.2),
+			expectedPearson:    pointer(6 / math.Sqrt(60)),
+			expectedSpearman:   pointer(7 / math.Sqrt(90)),
+		},
+		"monotonic but not linear": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": 1.0, "b": 1.0}},
+				{ID: "r2", Data: map[string]any{"a": 2.0, "b": 4.0}},
+				{ID: "r3", Data: map[string]any{"a": 3.0, "b": 9.0}},
+			},
+			pathA:              "a",
+			pathB:              "b",
+			expectedCovariance: pointer(8.0 / 3),
+			expectedPearson:    pointer(8 / math.Sqrt(2*294.0/9)),
+			expectedSpearman:   pointer(1.0),
+		},
+		"negative correlation": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": 1.0, "b": 3.0}},
+				{ID: "r2", Data: map[string]any{"a": 2.0, "b": 2.0}},
+				{ID: "r3", Data: map[string]any{"a": 3.0, "b": 1.0}},
+			},
+			pathA:              "a",
+			pathB:              "b",
+			expectedCovariance: pointer(-2.0 / 3),
+			expectedPearson:    pointer(-1.0),
+			expectedSpearman:   pointer(-1.0),
+		},
+		"constant values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": 1.0, "b": 3.0}},
+				{ID: "r2", Data: map[string]any{"a": 1.0, "b": 2.0}},
+			},
+			pathA:              "a",
+			pathB:              "b",
+			expectedCovariance: pointer(0.0),
+		},
+		"wildcard paths": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"items": []any{
+					map[string]any{"amount": 10.0, "discount": 1.0},
+					map[string]any{"amount": 20.0, "discount": 2.0},
+				}}},
+				{ID: "r2", Data: map[string]any{"items": []any{
+					map[string]any{"amount": 30.0, "discount": 3.0},
+					map[string]any{"amount": 40.0},
+				}}},
+			},
+			pathA:              "items.*.amount",
+			pathB:              "items.*.discount",
+			expectedCovariance: pointer(20.0 / 3),
+			expectedPearson:    pointer(1.0),
+			expectedSpearman:   pointer(1.0),
+		},
+		"mismatching paths": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": 1.0, "items": []any{1.0, 2.0}}},
+			},
+			pathA:       "a",
+			pathB:       "items.*",
+			expectError: true,
+		},
+		"non numeric values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"a": "not number", "b": 1.0}},
+			},
+			pathA:       "a",
+			pathB:       "b",
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			covariance, covarianceErr := record.Covariance(c.records, c.pathA, c.pathB)
+			pearson, pearsonErr := record.PearsonCorrelation(c.records, c.pathA, c.pathB)
+			spearman, spearmanErr := record.SpearmanCorrelation(c.records, c.pathA, c.pathB)
+			if c.expectError {
+				assert.Error(t, covarianceErr)
+				assert.Error(t, pearsonErr)
+				assert.Error(t, spearmanErr)
+				return
+			}
+			require.NoError(t, covarianceErr)
+			require.NoError(t, pearsonErr)
+			require.NoError(t, spearmanErr)
+			assertFloatPointer(t, c.expectedCovariance, covariance)
+			assertFloatPointer(t, c.expectedPearson, pearson)
+			assertFloatPointer(t, c.expectedSpearman, spearman)
+		})
+	}
+}
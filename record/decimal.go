@@ -0,0 +1,65 @@
+package record
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// decimalPattern matches plain decimal numbers with an optional exponent.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// visitDecimal visits all values of the provided path as arbitrary-precision
+// decimals together with the number of their decimal places.
+func visitDecimal(records []*api.Record, path string, visitor func(val *big.Rat, scale int) error) error {
+	return Visit(records, path, func(val any, _ *api.Record) error {
+		if val == nil {
+			return nil
+		}
+		var text string
+		switch v := val.(type) {
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			text = v
+		default:
+			return fmt.Errorf("invalid type while extracting number from path %v, expected numeric value but received %T", path, val)
+		}
+		if !decimalPattern.MatchString(text) {
+			return fmt.Errorf("invalid decimal value %q for path %v", text, path)
+		}
+		decimal, ok := new(big.Rat).SetString(text)
+		if !ok {
+			return fmt.Errorf("invalid decimal value %q for path %v", text, path)
+		}
+		return visitor(decimal, decimalScale(text))
+	})
+}
+
+// decimalScale returns the number of decimal places of a decimal string,
+// taking an exponent into account.
+func decimalScale(text string) int {
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(text), "e")
+	scale := 0
+	if _, fraction, ok := strings.Cut(mantissa, "."); ok {
+		scale = len(fraction)
+	}
+	if exp, err := strconv.Atoi(exponent); err == nil {
+		scale -= exp
+	}
+	return max(scale, 0)
+}
+
+// formatDecimal returns the decimal string of the value rounded to the provided
+// number of decimal places, without a sign for values rounded to zero.
+func formatDecimal(val *big.Rat, scale int) string {
+	text := val.FloatString(scale)
+	if strings.Trim(text, "-0.") == "" {
+		return strings.TrimPrefix(text, "-")
+	}
+	return text
+}
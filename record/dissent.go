@@ -0,0 +1,130 @@
+package record
+
+import (
+	"cmp"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// DissentEntry describes how much a single record disagrees with the majority
+// values of all records.
+type DissentEntry struct {
+	Record *api.Record `json:"record"`
+	// Score is the sum of the weights of all paths the record disagrees on.
+	Score float64 `json:"score"`
+	// Disagreements contains the paths the record disagrees on in the order of
+	// the requested paths.
+	Disagreements []*PathDisagreement `json:"disagreements"`
+}
+
+// PathDisagreement describes a single path where a record disagrees with the
+// majority value.
+type PathDisagreement struct {
+	Path string `json:"path"`
+	// Value is the first value of the record for that path.
+	Value any `json:"value"`
+	// Majority is the most common value of all records.
+	Majority any `json:"majority"`
+	// Weight is the Confidence of the path.
+	Weight float64 `json:"weight"`
+}
+
+// DissentingRecords returns all records ranked by how much they disagree with
+// the majority values for the provided paths.
+//
+// A record disagrees on a path if it has values for that path, but none of
+// them is equal to the majority value. Each disagreement is weighted with the
+// Confidence of the path, so that disagreeing on a path where all other
+// records agree weighs more than disagreeing on an already inconsistent path.
+//
+// Values are compared the same way as in Mode. If multiple values share the
+// highest frequency, then there is no majority value and no record disagrees
+// on that path.
+//
+// Records with the same score keep their original order.
+func DissentingRecords(records []*api.Record, paths []string, caseSensitive bool) ([]*DissentEntry, error) {
+	majorities, err := pathMajorities(records, paths, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]float64, len(paths))
+	for i, path := range paths {
+		if majorities[i] == nil {
+			continue
+		}
+		confidence, err := Confidence(records, path, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = *confidence
+	}
+
+	result := make([]*DissentEntry, 0, len(records))
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		entry, err := dissentEntry(record, paths, caseSensitive, majorities, weights)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entry)
+	}
+	slices.SortStableFunc(result, func(a, b *DissentEntry) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return result, nil
+}
+
+// dissentEntry collects all disagreements of the record with the majority
+// values.
+func dissentEntry(record *api.Record, paths []string, caseSensitive bool, majorities []*weightedValue, weights []float64) (*DissentEntry, error) {
+	entry := &DissentEntry{
+		Record:        record,
+		Disagreements: []*PathDisagreement{},
+	}
+	for i, path := range paths {
+		if majorities[i] == nil {
+			continue
+		}
+		disagreement, err := pathDisagreement(record, path, caseSensitive, majorities[i])
+		if err != nil {
+			return nil, err
+		}
+		if disagreement != nil {
+			disagreement.Weight = weights[i]
+			entry.Score += weights[i]
+			entry.Disagreements = append(entry.Disagreements, disagreement)
+		}
+	}
+	return entry, nil
+}
+
+// pathDisagreement returns the disagreement of the record for the path or
+// null if the record has no values or agrees with the majority value.
+func pathDisagreement(record *api.Record, path string, caseSensitive bool, majority *weightedValue) (*PathDisagreement, error) {
+	var first any
+	agrees := false
+	err := Visit([]*api.Record{record}, path, func(v any, _ *api.Record) error {
+		val, err := validateString(v, caseSensitive)
+		if err != nil || val == nil {
+			return err
+		}
+		if first == nil {
+			first = v
+		}
+		if *val == majority.key {
+			agrees = true
+		}
+		return nil
+	})
+	if err != nil || first == nil || agrees {
+		return nil, err
+	}
+	return &PathDisagreement{
+		Path:     path,
+		Value:    first,
+		Majority: majority.value,
+	}, nil
+}
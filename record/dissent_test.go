@@ -0,0 +1,89 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestDissentingRecords(t *testing.T) {
+	r1 := &api.Record{ID: "r1", Data: map[string]any{"name": "Alice", "city": "Berlin", "country": "DE", "tag": "a"}}
+	r2 := &api.Record{ID: "r2", Data: map[string]any{"name": "alice", "city": "Berlin", "country": "DE", "tag": "b"}}
+	r3 := &api.Record{ID: "r3", Data: map[string]any{"name": "Alice", "city": "Munich", "country": "DE"}}
+	r4 := &api.Record{ID: "r4", Data: map[string]any{"name": "Bob", "city": "Paris", "country": "FR"}}
+	r5 := &api.Record{ID: "r5", Data: map[string]any{}}
+	records := []*api.Record{r1, r2, r3, r4, r5, nil}
+
+	cases := map[string]struct {
+		records       []*api.Record
+		paths         []string
+		caseSensitive bool
+		expected      []*record.DissentEntry
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			paths:    []string{"name"},
+			expected: []*record.DissentEntry{},
+		},
+		"case insensitive": {
+			records: records,
+			paths:   []string{"name", "city", "country", "tag"},
+			expected: []*record.DissentEntry{
+				{
+					Record: r4,
+					Score:  0.625 + 0.375 + 0.625,
+					Disagreements: []*record.PathDisagreement{
+						{Path: "name", Value: "Bob", Majority: "Alice", Weight: 0.625},
+						{Path: "city", Value: "Paris", Majority: "Berlin", Weight: 0.375},
+						{Path: "country", Value: "FR", Majority: "DE", Weight: 0.625},
+					},
+				},
+				{
+					Record: r3,
+					Score:  0.375,
+					Disagreements: []*record.PathDisagreement{
+						{Path: "city", Value: "Munich", Majority: "Berlin", Weight: 0.375},
+					},
+				},
+				{Record: r1, Disagreements: []*record.PathDisagreement{}},
+				{Record: r2, Disagreements: []*record.PathDisagreement{}},
+				{Record: r5, Disagreements: []*record.PathDisagreement{}},
+			},
+		},
+		"case sensitive": {
+			records:       records,
+			paths:         []string{"name"},
+			caseSensitive: true,
+			expected: []*record.DissentEntry{
+				{
+					Record: r2,
+					Score:  0.375,
+					Disagreements: []*record.PathDisagreement{
+						{Path: "name", Value: "alice", Majority: "Alice", Weight: 0.375},
+					},
+				},
+				{
+					Record: r4,
+					Score:  0.375,
+					Disagreements: []*record.PathDisagreement{
+						{Path: "name", Value: "Bob", Majority: "Alice", Weight: 0.375},
+					},
+				},
+				{Record: r1, Disagreements: []*record.PathDisagreement{}},
+				{Record: r3, Disagreements: []*record.PathDisagreement{}},
+				{Record: r5, Disagreements: []*record.PathDisagreement{}},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.DissentingRecords(c.records, c.paths, c.caseSensitive)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
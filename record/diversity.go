@@ -0,0 +1,63 @@
+package record
+
+import (
+	"math"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// DiversityResult contains several metrics describing how diverse the values
+// of a path are.
+type DiversityResult struct {
+	// Count is the number of non-null values.
+	Count int `json:"count"`
+	// Distinct is the number of unique non-null values.
+	Distinct int `json:"distinct"`
+	// Entropy is the Shannon entropy in bits.
+	Entropy float64 `json:"entropy"`
+	// NormalizedEntropy is the entropy divided by its maximum possible value
+	// for the number of distinct values, ranging from 0 to 1. It is 0 if there
+	// is only one distinct value.
+	NormalizedEntropy float64 `json:"normalizedEntropy"`
+	// SimpsonDominance is the probability that two randomly chosen values are
+	// equal, ranging from 0 to 1. It is the same value as returned by
+	// Confidence.
+	SimpsonDominance float64 `json:"simpsonDominance"`
+	// TopShare is the share of the most common value, ranging from 0 to 1.
+	TopShare float64 `json:"topShare"`
+}
+
+// Diversity returns entropy-based and dominance-based diversity metrics for
+// the provided path.
+//
+// Values are compared the same way as in Confidence. All metrics are
+// calculated from the same frequencies.
+//
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func Diversity(records []*api.Record, path string, caseSensitive bool) (*DiversityResult, error) {
+	frequencies, valueCount, err := collectFrequencies(records, path, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	if valueCount == 0 {
+		return nil, nil
+	}
+
+	result := &DiversityResult{
+		Count:    valueCount,
+		Distinct: len(frequencies),
+	}
+	topFrequency := 0
+	for _, freq := range frequencies {
+		prob := float64(freq) / float64(valueCount)
+		result.Entropy -= prob * math.Log2(prob)
+		result.SimpsonDominance += prob * prob
+		topFrequency = max(topFrequency, freq)
+	}
+	if result.Distinct > 1 {
+		result.NormalizedEntropy = result.Entropy / math.Log2(float64(result.Distinct))
+	}
+	result.TopShare = float64(topFrequency) / float64(valueCount)
+	return result, nil
+}
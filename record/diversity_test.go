@@ -0,0 +1,111 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestDiversity(t *testing.T) {
+	cases := map[string]struct {
+		records       []*api.Record
+		caseSensitive bool
+		expected      *record.DiversityResult
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			expected: nil,
+		},
+		"list with all nil values": {
+			records:  []*api.Record{nil, {ID: "r1", Data: map[string]any{}}},
+			expected: nil,
+		},
+		"single value": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a"}},
+				{ID: "r2", Data: map[string]any{"text": "A"}},
+			},
+			expected: &record.DiversityResult{
+				Count:             2,
+				Distinct:          1,
+				Entropy:           0,
+				NormalizedEntropy: 0,
+				SimpsonDominance:  1,
+				TopShare:          1,
+			},
+		},
+		"uniform values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a"}},
+				{ID: "r2", Data: map[string]any{"text": "b"}},
+				{ID: "r3", Data: map[string]any{"text": "c"}},
+				{ID: "r4", Data: map[string]any{"text": "d"}},
+			},
+			expected: &record.DiversityResult{
+				Count:             4,
+				Distinct:          4,
+				Entropy:           2,
+				NormalizedEntropy: 1,
+				SimpsonDominance:  0.25,
+				TopShare:          0.25,
+			},
+		},
+		"skewed values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a"}},
+				{ID: "r2", Data: map[string]any{"text": "a"}},
+				{ID: "r3", Data: map[string]any{"text": "a"}},
+				{ID: "r4", Data: map[string]any{"text": "b"}},
+				{ID: "r5", Data: map[string]any{"text": nil}},
+			},
+			expected: &record.DiversityResult{
+				Count:             4,
+				Distinct:          2,
+				Entropy:           0.8112781244591328,
+				NormalizedEntropy: 0.8112781244591328,
+				SimpsonDominance:  0.625,
+				TopShare:          0.75,
+			},
+		},
+		"case sensitive": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a"}},
+				{ID: "r2", Data: map[string]any{"text": "A"}},
+			},
+			caseSensitive: true,
+			expected: &record.DiversityResult{
+				Count:             2,
+				Distinct:          2,
+				Entropy:           1,
+				NormalizedEntropy: 1,
+				SimpsonDominance:  0.5,
+				TopShare:          0.5,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Diversity(c.records, "text", c.caseSensitive)
+			require.NoError(t, err)
+			if c.expected == nil {
+				assert.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			assert.Equal(t, c.expected.Count, actual.Count)
+			assert.Equal(t, c.expected.Distinct, actual.Distinct)
+			assert.InDelta(t, c.expected.Entropy, actual.Entropy, 1e-9)
+			assert.InDelta(t, c.expected.NormalizedEntropy, actual.NormalizedEntropy, 1e-9)
+			assert.InDelta(t, c.expected.SimpsonDominance, actual.SimpsonDominance, 1e-9)
+			assert.InDelta(t, c.expected.TopShare, actual.TopShare, 1e-9)
+
+			confidence, err := record.Confidence(c.records, "text", c.caseSensitive)
+			require.NoError(t, err)
+			assert.InDelta(t, *confidence, actual.SimpsonDominance, 1e-9)
+		})
+	}
+}
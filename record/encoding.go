@@ -0,0 +1,195 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// filterConditionData is the serialized representation of a FilterCondition.
+//
+// Time values are kept as strings so that they can be parsed using the same
+// formats as the record values.
+type filterConditionData struct {
+	Path string `json:"path" yaml:"path"`
+
+	Equals any     `json:"equals" yaml:"equals"`
+	IsNull *bool   `json:"isNull" yaml:"isNull"`
+	IsType *string `json:"isType" yaml:"isType"`
+
+	StartsWith *string `json:"startsWith" yaml:"startsWith"`
+	EndsWith   *string `json:"endsWith" yaml:"endsWith"`
+	LikeRegex  *string `json:"likeRegex" yaml:"likeRegex"`
+
+	LessThan      *float64 `json:"lessThan" yaml:"lessThan"`
+	LessEquals    *float64 `json:"lessEquals" yaml:"lessEquals"`
+	GreaterThan   *float64 `json:"greaterThan" yaml:"greaterThan"`
+	GreaterEquals *float64 `json:"greaterEquals" yaml:"greaterEquals"`
+
+	After  *string `json:"after" yaml:"after"`
+	Since  *string `json:"since" yaml:"since"`
+	Before *string `json:"before" yaml:"before"`
+	Until  *string `json:"until" yaml:"until"`
+
+	MinLength *int `json:"minLength" yaml:"minLength"`
+	MaxLength *int `json:"maxLength" yaml:"maxLength"`
+
+	Invert        *bool `json:"invert" yaml:"invert"`
+	CaseSensitive *bool `json:"caseSensitive" yaml:"caseSensitive"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// Unknown fields are rejected and the resulting FilterCondition is validated.
+func (c *FilterCondition) UnmarshalJSON(data []byte) error {
+	d := &filterConditionData{}
+	if err := decodeJSONStrict(data, d); err != nil {
+		return err
+	}
+	return c.fromData(d)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+//
+// Unknown fields are rejected and the resulting FilterCondition is validated.
+func (c *FilterCondition) UnmarshalYAML(value *yaml.Node) error {
+	d := &filterConditionData{}
+	if err := decodeYAMLStrict(value, d); err != nil {
+		return err
+	}
+	return c.fromData(d)
+}
+
+func (c *FilterCondition) fromData(d *filterConditionData) error {
+	condition := FilterCondition{
+		Path:          d.Path,
+		Equals:        d.Equals,
+		IsNull:        d.IsNull,
+		IsType:        d.IsType,
+		StartsWith:    d.StartsWith,
+		EndsWith:      d.EndsWith,
+		LikeRegex:     d.LikeRegex,
+		LessThan:      d.LessThan,
+		LessEquals:    d.LessEquals,
+		GreaterThan:   d.GreaterThan,
+		GreaterEquals: d.GreaterEquals,
+		MinLength:     d.MinLength,
+		MaxLength:     d.MaxLength,
+		Invert:        d.Invert,
+		CaseSensitive: d.CaseSensitive,
+	}
+	var err error
+	if condition.After, err = parseFieldTime("after", d.After); err != nil {
+		return err
+	}
+	if condition.Since, err = parseFieldTime("since", d.Since); err != nil {
+		return err
+	}
+	if condition.Before, err = parseFieldTime("before", d.Before); err != nil {
+		return err
+	}
+	if condition.Until, err = parseFieldTime("until", d.Until); err != nil {
+		return err
+	}
+	if err := condition.Validate(); err != nil {
+		return err
+	}
+	*c = condition
+	return nil
+}
+
+func parseFieldTime(field string, val *string) (*time.Time, error) {
+	if val == nil {
+		return nil, nil
+	}
+	t, err := parseTime(*val)
+	if err != nil {
+		return nil, &ValidationError{Field: field, Message: err.Error()}
+	}
+	return t, nil
+}
+
+// sortCriteriaData is the serialized representation of a SortCriteria.
+//
+// It has the same fields, but none of the methods, so that it can be decoded
+// without recursion.
+type sortCriteriaData SortCriteria
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// Unknown fields are rejected and the resulting SortCriteria is validated.
+func (c *SortCriteria) UnmarshalJSON(data []byte) error {
+	d := &sortCriteriaData{}
+	if err := decodeJSONStrict(data, d); err != nil {
+		return err
+	}
+	return c.fromData(d)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+//
+// Unknown fields are rejected and the resulting SortCriteria is validated.
+func (c *SortCriteria) UnmarshalYAML(value *yaml.Node) error {
+	d := &sortCriteriaData{}
+	if err := decodeYAMLStrict(value, d); err != nil {
+		return err
+	}
+	return c.fromData(d)
+}
+
+func (c *SortCriteria) fromData(d *sortCriteriaData) error {
+	criteria := SortCriteria(*d)
+	if err := criteria.Validate(); err != nil {
+		return err
+	}
+	*c = criteria
+	return nil
+}
+
+// decodeJSONStrict decodes the data into v, rejecting unknown fields.
+//
+// Unlike encoding/json, field names must match their tags exactly, including
+// their case.
+func decodeJSONStrict(data []byte, v any) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	known := jsonFieldNames(v)
+	for name := range fields {
+		if !known[name] {
+			return fmt.Errorf("json: unknown field %q", name)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func decodeYAMLStrict(value *yaml.Node, v any) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+// jsonFieldNames returns the JSON field names of the struct v points to.
+func jsonFieldNames(v any) map[string]bool {
+	t := reflect.TypeOf(v).Elem()
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
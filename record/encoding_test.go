@@ -0,0 +1,314 @@
+package record_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	insights "github.com/tilotech/tilores-insights/record"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFilterConditionUnmarshal(t *testing.T) {
+	cases := map[string]struct {
+		json          string
+		yaml          string
+		expected      *insights.FilterCondition
+		expectError   bool
+		expectedField string
+	}{
+		"all criteria": {
+			json: `{
+				"path": "value",
+				"equals": "a",
+				"isNull": false,
+				"isType": "string",
+				"startsWith": "a",
+				"endsWith": "b",
+				"likeRegex": "a.*b",
+				"lessThan": 1,
+				"lessEquals": 2,
+				"greaterThan": 3,
+				"greaterEquals": 4,
+				"minLength": 1,
+				"maxLength": 5,
+				"invert": true,
+				"caseSensitive": true
+			}`,
+			yaml: `
+path: value
+equals: a
+isNull: false
+isType: string
+startsWith: a
+endsWith: b
+likeRegex: a.*b
+lessThan: 1
+lessEquals: 2
+greaterThan: 3
+greaterEquals: 4
+minLength: 1
+maxLength: 5
+invert: true
+caseSensitive: true
+`,
+			expected: &insights.FilterCondition{
+				Path:          "value",
+				Equals:        "a",
+				IsNull:        pointer(false),
+				IsType:        pointer("string"),
+				StartsWith:    pointer("a"),
+				EndsWith:      pointer("b"),
+				LikeRegex:     pointer("a.*b"),
+				LessThan:      pointer(1.0),
+				LessEquals:    pointer(2.0),
+				GreaterThan:   pointer(3.0),
+				GreaterEquals: pointer(4.0),
+				MinLength:     pointer(1),
+				MaxLength:     pointer(5),
+				Invert:        pointer(true),
+				CaseSensitive: pointer(true),
+			},
+		},
+		"time criteria": {
+			json: `{
+				"path": "time",
+				"after": "2022-01-01T00:00:00Z",
+				"since": "2023-01-01T02:00:00+02:00",
+				"before": "2024-01-01T00:00:00.123456",
+				"until": "2025-01-01T00:00:00.5Z"
+			}`,
+			yaml: `
+path: time
+after: 2022-01-01T00:00:00Z
+since: "2023-01-01T02:00:00+02:00"
+before: 2024-01-01T00:00:00.123456
+until: 2025-01-01T00:00:00.5Z
+`,
+			expected: &insights.FilterCondition{
+				Path:   "time",
+				After:  pointer(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Since:  pointer(time.Date(2023, 1, 1, 2, 0, 0, 0, time.FixedZone("", 2*60*60))),
+				Before: pointer(time.Date(2024, 1, 1, 0, 0, 0, 123456000, time.UTC)),
+				Until:  pointer(time.Date(2025, 1, 1, 0, 0, 0, 500000000, time.UTC)),
+			},
+		},
+		"unknown field": {
+			json:        `{"path": "value", "equal": "a"}`,
+			yaml:        "path: value\nequal: a\n",
+			expectError: true,
+		},
+		"field with different case": {
+			json:        `{"PATH": "value", "EQUALS": "a"}`,
+			yaml:        "PATH: value\nEQUALS: a\n",
+			expectError: true,
+		},
+		"missing path": {
+			json:          `{"equals": "a"}`,
+			yaml:          "equals: a\n",
+			expectError:   true,
+			expectedField: "path",
+		},
+		"invalid time": {
+			json:          `{"path": "time", "after": "yesterday"}`,
+			yaml:          "path: time\nafter: yesterday\n",
+			expectError:   true,
+			expectedField: "after",
+		},
+		"invalid type": {
+			json:          `{"path": "value", "isType": "text"}`,
+			yaml:          "path: value\nisType: text\n",
+			expectError:   true,
+			expectedField: "isType",
+		},
+		"invalid regex": {
+			json:          `{"path": "value", "likeRegex": "("}`,
+			yaml:          "path: value\nlikeRegex: (\n",
+			expectError:   true,
+			expectedField: "likeRegex",
+		},
+		"negative length": {
+			json:          `{"path": "value", "minLength": -1}`,
+			yaml:          "path: value\nminLength: -1\n",
+			expectError:   true,
+			expectedField: "minLength",
+		},
+		"max length less than min length": {
+			json:          `{"path": "value", "minLength": 2, "maxLength": 1}`,
+			yaml:          "path: value\nminLength: 2\nmaxLength: 1\n",
+			expectError:   true,
+			expectedField: "maxLength",
+		},
+		"numeric and time criteria": {
+			json:          `{"path": "value", "lessThan": 2, "before": "2022-01-01T00:00:00Z"}`,
+			yaml:          "path: value\nlessThan: 2\nbefore: 2022-01-01T00:00:00Z\n",
+			expectError:   true,
+			expectedField: "before",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name+" json", func(t *testing.T) {
+			actual := &insights.FilterCondition{}
+			err := json.Unmarshal([]byte(c.json), actual)
+			assertUnmarshalResult(t, c.expected, actual, err, c.expectError, c.expectedField)
+		})
+		t.Run(name+" yaml", func(t *testing.T) {
+			actual := &insights.FilterCondition{}
+			err := yaml.Unmarshal([]byte(c.yaml), actual)
+			assertUnmarshalResult(t, c.expected, actual, err, c.expectError, c.expectedField)
+		})
+	}
+}
+
+func TestFilterConditionMarshal(t *testing.T) {
+	condition := &insights.FilterCondition{
+		Path:          "value",
+		Equals:        "a",
+		StartsWith:    pointer("a"),
+		After:         pointer(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		MinLength:     pointer(2),
+		CaseSensitive: pointer(true),
+	}
+
+	data, err := json.Marshal(condition)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"path":"value","equals":"a","startsWith":"a","after":"2022-01-01T00:00:00Z","minLength":2,"caseSensitive":true}`, string(data))
+
+	jsonRoundTrip := &insights.FilterCondition{}
+	require.NoError(t, json.Unmarshal(data, jsonRoundTrip))
+	assert.Equal(t, condition, jsonRoundTrip)
+
+	data, err = yaml.Marshal(condition)
+	require.NoError(t, err)
+	yamlRoundTrip := &insights.FilterCondition{}
+	require.NoError(t, yaml.Unmarshal(data, yamlRoundTrip))
+	assert.Equal(t, condition, yamlRoundTrip)
+}
+
+func TestSortCriteriaUnmarshal(t *testing.T) {
+	cases := map[string]struct {
+		json          string
+		yaml          string
+		expected      *insights.SortCriteria
+		expectError   bool
+		expectedField string
+	}{
+		"ascending": {
+			json: `{"path": "value", "asc": true}`,
+			yaml: "path: value\nasc: true\n",
+			expected: &insights.SortCriteria{
+				Path: "value",
+				ASC:  true,
+			},
+		},
+		"with type": {
+			json: `{"path": "value", "type": "natural"}`,
+			yaml: "path: value\ntype: natural\n",
+			expected: &insights.SortCriteria{
+				Path: "value",
+				Type: insights.SortTypeNatural,
+			},
+		},
+		"all options": {
+			json: `{"path": "value", "asc": true, "type": "string", "nulls": "first", "caseSensitive": true}`,
+			yaml: "path: value\nasc: true\ntype: string\nnulls: first\ncaseSensitive: true\n",
+			expected: &insights.SortCriteria{
+				Path:          "value",
+				ASC:           true,
+				Type:          insights.SortTypeString,
+				Nulls:         insights.SortNullsFirst,
+				CaseSensitive: true,
+			},
+		},
+		"invalid collation": {
+			json:          `{"path": "value", "collation": "not a language"}`,
+			yaml:          "path: value\ncollation: not a language\n",
+			expectError:   true,
+			expectedField: "collation",
+		},
+		"invalid nulls": {
+			json:          `{"path": "value", "nulls": "middle"}`,
+			yaml:          "path: value\nnulls: middle\n",
+			expectError:   true,
+			expectedField: "nulls",
+		},
+		"invalid type": {
+			json:          `{"path": "value", "type": "alphabetical"}`,
+			yaml:          "path: value\ntype: alphabetical\n",
+			expectError:   true,
+			expectedField: "type",
+		},
+		"default order": {
+			json: `{"path": "value"}`,
+			yaml: "path: value\n",
+			expected: &insights.SortCriteria{
+				Path: "value",
+			},
+		},
+		"unknown field": {
+			json:        `{"path": "value", "ascending": true}`,
+			yaml:        "path: value\nascending: true\n",
+			expectError: true,
+		},
+		"field with different case": {
+			json:        `{"path": "value", "ASC": true}`,
+			yaml:        "path: value\nASC: true\n",
+			expectError: true,
+		},
+		"missing path": {
+			json:          `{"asc": true}`,
+			yaml:          "asc: true\n",
+			expectError:   true,
+			expectedField: "path",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name+" json", func(t *testing.T) {
+			actual := &insights.SortCriteria{}
+			err := json.Unmarshal([]byte(c.json), actual)
+			assertUnmarshalResult(t, c.expected, actual, err, c.expectError, c.expectedField)
+		})
+		t.Run(name+" yaml", func(t *testing.T) {
+			actual := &insights.SortCriteria{}
+			err := yaml.Unmarshal([]byte(c.yaml), actual)
+			assertUnmarshalResult(t, c.expected, actual, err, c.expectError, c.expectedField)
+		})
+	}
+}
+
+func TestSortCriteriaMarshal(t *testing.T) {
+	criteria := []*insights.SortCriteria{
+		{
+			Path: "value",
+			ASC:  true,
+		},
+	}
+
+	data, err := json.Marshal(criteria)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"path":"value","asc":true}]`, string(data))
+
+	roundTrip := []*insights.SortCriteria{}
+	require.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.Equal(t, criteria, roundTrip)
+}
+
+func assertUnmarshalResult[T any](t *testing.T, expected *T, actual *T, err error, expectError bool, expectedField string) {
+	t.Helper()
+	if !expectError {
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+		return
+	}
+	require.Error(t, err)
+	if expectedField != "" {
+		var validationErr *insights.ValidationError
+		require.True(t, errors.As(err, &validationErr), "expected validation error, got %v", err)
+		assert.Equal(t, expectedField, validationErr.Field)
+	}
+}
@@ -0,0 +1,120 @@
+package record
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// ExtremeResult contains the record holding the lowest or highest value of a
+// path.
+type ExtremeResult struct {
+	// Record is the first record holding the extreme value.
+	Record *api.Record `json:"record"`
+	// Value is the extreme value as it is stored in Record.
+	Value any `json:"value"`
+	// Ties contains all records holding the extreme value in the order of
+	// their occurrence, including Record.
+	//
+	// Ties is only set if requested.
+	Ties []*api.Record `json:"ties,omitempty"`
+}
+
+// extremeValue is a single non-null value of a path together with its record
+// and its comparable representation.
+type extremeValue struct {
+	value  any
+	record *api.Record
+	number *float64
+	text   string
+}
+
+// extremeBy returns the record with the extreme value, where better decides
+// whether the comparison result of a candidate against the current extreme
+// value makes the candidate the new extreme.
+func extremeBy(records []*api.Record, path string, caseSensitive bool, withTies bool, better func(c int) bool) (*ExtremeResult, error) {
+	values, compare, err := collectExtremeValues(records, path, caseSensitive)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	extreme := values[0]
+	result := newExtremeResult(extreme, withTies)
+	for _, v := range values[1:] {
+		c := compare(v, extreme)
+		switch {
+		case better(c):
+			extreme = v
+			result = newExtremeResult(extreme, withTies)
+		case c == 0 && withTies && !slices.Contains(result.Ties, v.record):
+			result.Ties = append(result.Ties, v.record)
+		}
+	}
+	return result, nil
+}
+
+// collectExtremeValues returns all non-null values of the path together with
+// the function to compare them.
+//
+// If all values are numeric, they are compared as numbers, otherwise as
+// strings. Mixing numbers with non-numeric values raises an error.
+func collectExtremeValues(records []*api.Record, path string, caseSensitive bool) ([]*extremeValue, func(a, b *extremeValue) int, error) {
+	values := []*extremeValue{}
+	hasNumber := false
+	hasNonNumeric := false
+	err := Visit(records, path, func(val any, record *api.Record) error {
+		if val == nil {
+			return nil
+		}
+		v := &extremeValue{value: val, record: record}
+		if number, err := validateNumber(val, path); err == nil {
+			v.number = number
+		} else {
+			hasNonNumeric = true
+		}
+		if _, ok := val.(float64); ok {
+			hasNumber = true
+		}
+		values = append(values, v)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasNonNumeric {
+		return values, compareExtremeNumber, nil
+	}
+	if hasNumber {
+		return nil, nil, fmt.Errorf("invalid values for path %v, expected either only numeric or only non-numeric values", path)
+	}
+	for _, v := range values {
+		text, err := validateString(v.value, caseSensitive)
+		if err != nil {
+			return nil, nil, err
+		}
+		v.text = *text
+	}
+	return values, compareExtremeText, nil
+}
+
+func compareExtremeNumber(a, b *extremeValue) int {
+	return cmp.Compare(*a.number, *b.number)
+}
+
+func compareExtremeText(a, b *extremeValue) int {
+	return strings.Compare(a.text, b.text)
+}
+
+func newExtremeResult(v *extremeValue, withTies bool) *ExtremeResult {
+	result := &ExtremeResult{
+		Record: v.record,
+		Value:  v.value,
+	}
+	if withTies {
+		result.Ties = []*api.Record{v.record}
+	}
+	return result
+}
@@ -1,11 +1,13 @@
 package record
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	api "github.com/tilotech/tilores-plugin-api"
 )
@@ -17,28 +19,78 @@ import (
 //
 // Some criteria are mutually exclusive due to either logical reasons or type constraints. E.g. lessThan and after
 // cannot be used together due to different type expectations.
+//
+// IsType accepts one of the JSON type names string, number, bool, array or object. MinLength and MaxLength check the
+// number of characters of a string, the number of elements of an array or the number of keys of an object.
 type FilterCondition struct {
-	Path string
+	Path string `json:"path" yaml:"path"`
+
+	Equals any     `json:"equals,omitempty" yaml:"equals,omitempty"`
+	IsNull *bool   `json:"isNull,omitempty" yaml:"isNull,omitempty"`
+	IsType *string `json:"isType,omitempty" yaml:"isType,omitempty"`
 
-	Equals any
-	IsNull *bool
+	StartsWith *string `json:"startsWith,omitempty" yaml:"startsWith,omitempty"`
+	EndsWith   *string `json:"endsWith,omitempty" yaml:"endsWith,omitempty"`
+	LikeRegex  *string `json:"likeRegex,omitempty" yaml:"likeRegex,omitempty"`
 
-	StartsWith *string
-	EndsWith   *string
-	LikeRegex  *string
+	LessThan      *float64 `json:"lessThan,omitempty" yaml:"lessThan,omitempty"`
+	LessEquals    *float64 `json:"lessEquals,omitempty" yaml:"lessEquals,omitempty"`
+	GreaterThan   *float64 `json:"greaterThan,omitempty" yaml:"greaterThan,omitempty"`
+	GreaterEquals *float64 `json:"greaterEquals,omitempty" yaml:"greaterEquals,omitempty"`
 
-	LessThan      *float64
-	LessEquals    *float64
-	GreaterThan   *float64
-	GreaterEquals *float64
+	After  *time.Time `json:"after,omitempty" yaml:"after,omitempty"`
+	Since  *time.Time `json:"since,omitempty" yaml:"since,omitempty"`
+	Before *time.Time `json:"before,omitempty" yaml:"before,omitempty"`
+	Until  *time.Time `json:"until,omitempty" yaml:"until,omitempty"`
 
-	After  *time.Time
-	Since  *time.Time
-	Before *time.Time
-	Until  *time.Time
+	MinLength *int `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+
+	Invert        *bool `json:"invert,omitempty" yaml:"invert,omitempty"`
+	CaseSensitive *bool `json:"caseSensitive,omitempty" yaml:"caseSensitive,omitempty"`
+}
 
-	Invert        *bool
-	CaseSensitive *bool
+// Validate checks the FilterCondition for invalid or conflicting criteria.
+//
+// The returned error is a *ValidationError that refers to the offending field.
+func (c *FilterCondition) Validate() error {
+	if c.Path == "" {
+		return &ValidationError{Field: "path", Message: "must not be empty"}
+	}
+	if c.IsType != nil && !isFilterType(*c.IsType) {
+		return &ValidationError{Field: "isType", Message: fmt.Sprintf("unsupported type %q", *c.IsType)}
+	}
+	if c.LikeRegex != nil {
+		if _, err := regexp.Compile(*c.LikeRegex); err != nil {
+			return &ValidationError{Field: "likeRegex", Message: err.Error()}
+		}
+	}
+	if c.MinLength != nil && *c.MinLength < 0 {
+		return &ValidationError{Field: "minLength", Message: "must not be negative"}
+	}
+	if c.MaxLength != nil && *c.MaxLength < 0 {
+		return &ValidationError{Field: "maxLength", Message: "must not be negative"}
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MaxLength < *c.MinLength {
+		return &ValidationError{Field: "maxLength", Message: "must not be less than minLength"}
+	}
+	if hasFilterNumericCriteria(c) && hasFilterTimeCriteria(c) {
+		return &ValidationError{Field: firstFilterTimeField(c), Message: "cannot be combined with numeric criteria"}
+	}
+	return nil
+}
+
+func firstFilterTimeField(c *FilterCondition) string {
+	switch {
+	case c.After != nil:
+		return "after"
+	case c.Since != nil:
+		return "since"
+	case c.Before != nil:
+		return "before"
+	default:
+		return "until"
+	}
 }
 
 // Filter returns a new RecordInsights that only contains the records for which
@@ -86,6 +138,9 @@ func checkFilterCondition(record *api.Record, condition *FilterCondition) (bool,
 	if !checkFilterCriteriaIsNull(record, condition) {
 		return false, nil
 	}
+	if keep, err := checkFilterCriteriaIsType(record, condition); !keep || err != nil {
+		return keep, err
+	}
 	if keep, err := checkFilterStringCriteria(record, condition); !keep || err != nil {
 		return keep, err
 	}
@@ -95,6 +150,9 @@ func checkFilterCondition(record *api.Record, condition *FilterCondition) (bool,
 	if keep, err := checkFilterTimeCriteria(record, condition); !keep || err != nil {
 		return keep, err
 	}
+	if keep, err := checkFilterLengthCriteria(record, condition); !keep || err != nil {
+		return keep, err
+	}
 	return true, nil
 }
 
@@ -109,6 +167,50 @@ func checkFilterCriteriaIsNull(record *api.Record, condition *FilterCondition) b
 	return value != nil
 }
 
+// Supported type names for the IsType filter criteria.
+const (
+	FilterTypeString = "string"
+	FilterTypeNumber = "number"
+	FilterTypeBool   = "bool"
+	FilterTypeArray  = "array"
+	FilterTypeObject = "object"
+)
+
+func checkFilterCriteriaIsType(record *api.Record, condition *FilterCondition) (bool, error) {
+	if condition.IsType == nil {
+		return true, nil
+	}
+	if !isFilterType(*condition.IsType) {
+		return false, fmt.Errorf("invalid type %q for isType criteria on path %v", *condition.IsType, condition.Path)
+	}
+	value := Extract(record, condition.Path)
+	return value != nil && valueTypeName(value) == *condition.IsType, nil
+}
+
+func isFilterType(name string) bool {
+	switch name {
+	case FilterTypeString, FilterTypeNumber, FilterTypeBool, FilterTypeArray, FilterTypeObject:
+		return true
+	}
+	return false
+}
+
+func valueTypeName(val any) string {
+	switch val.(type) {
+	case string:
+		return FilterTypeString
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return FilterTypeNumber
+	case bool:
+		return FilterTypeBool
+	case []any:
+		return FilterTypeArray
+	case map[string]any:
+		return FilterTypeObject
+	}
+	return ""
+}
+
 func hasFilterStringCriteria(condition *FilterCondition) bool {
 	return condition.Equals != nil ||
 		condition.StartsWith != nil ||
@@ -248,19 +350,19 @@ func checkFilterNumericCriteria(record *api.Record, condition *FilterCondition)
 	return true, nil
 }
 
-func checkFilterOpLessThan(a, b float64) bool {
+func checkFilterOpLessThan[T float64 | int](a, b T) bool {
 	return a < b
 }
 
-func checkFilterOpLessEqual(a, b float64) bool {
+func checkFilterOpLessEqual[T float64 | int](a, b T) bool {
 	return a <= b
 }
 
-func checkFilterOpGreaterThan(a, b float64) bool {
+func checkFilterOpGreaterThan[T float64 | int](a, b T) bool {
 	return a > b
 }
 
-func checkFilterOpGreaterEqual(a, b float64) bool {
+func checkFilterOpGreaterEqual[T float64 | int](a, b T) bool {
 	return a >= b
 }
 
@@ -313,6 +415,46 @@ func checkFilterOpUntil(a, b time.Time) bool {
 	return a.Equal(b) || a.Before(b)
 }
 
+func hasFilterLengthCriteria(condition *FilterCondition) bool {
+	return condition.MinLength != nil ||
+		condition.MaxLength != nil
+}
+
+func checkFilterLengthCriteria(record *api.Record, condition *FilterCondition) (bool, error) {
+	if !hasFilterLengthCriteria(condition) {
+		return true, nil
+	}
+
+	value, err := extractLength(record, condition.Path)
+	if err != nil {
+		return false, err
+	}
+
+	if !checkFilterCriteriaCompare(value, condition.MinLength, checkFilterOpGreaterEqual) {
+		return false, nil
+	}
+	if !checkFilterCriteriaCompare(value, condition.MaxLength, checkFilterOpLessEqual) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func extractLength(record *api.Record, path string) (*int, error) {
+	switch val := Extract(record, path).(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return pointer(utf8.RuneCountInString(val)), nil
+	case []any:
+		return pointer(len(val)), nil
+	case map[string]any:
+		return pointer(len(val)), nil
+	default:
+		return nil, fmt.Errorf("invalid type while extracting length from path %v, expected string, array or object but received %T", path, val)
+	}
+}
+
 func checkFilterCriteriaCompare[T any](value *T, test *T, op func(a, b T) bool) bool {
 	if test == nil {
 		return true
@@ -45,6 +45,20 @@ func TestFilter(t *testing.T) {
 			"numeric": 1.234567e+06,
 		},
 	}
+	r5 := &api.Record{
+		ID: "r5",
+		Data: map[string]any{
+			"list": []any{"a", "b", "c"},
+			"flag": true,
+		},
+	}
+	r6 := &api.Record{
+		ID: "r6",
+		Data: map[string]any{
+			"list": []any{"a"},
+			"flag": "true",
+		},
+	}
 	defaultRecords := []*api.Record{
 		r1, r2, r3,
 	}
@@ -654,6 +668,117 @@ func TestFilter(t *testing.T) {
 			},
 			expectError: true,
 		},
+		"is type string": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "numeric",
+					IsType: pointer(insights.FilterTypeString),
+				},
+			},
+			expected: []*api.Record{r2},
+		},
+		"is type number": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "numeric",
+					IsType: pointer(insights.FilterTypeNumber),
+				},
+			},
+			expected: []*api.Record{r1, r3},
+		},
+		"is type object": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "map",
+					IsType: pointer(insights.FilterTypeObject),
+				},
+			},
+			expected: []*api.Record{r1},
+		},
+		"is type array": {
+			records: []*api.Record{r1, r5, r6},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "list",
+					IsType: pointer(insights.FilterTypeArray),
+				},
+			},
+			expected: []*api.Record{r5, r6},
+		},
+		"is type bool": {
+			records: []*api.Record{r1, r5, r6},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "flag",
+					IsType: pointer(insights.FilterTypeBool),
+				},
+			},
+			expected: []*api.Record{r5},
+		},
+		"is type, invalid type name": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "value",
+					IsType: pointer("text"),
+				},
+			},
+			expectError: true,
+		},
+		"min length array": {
+			records: []*api.Record{r1, r5, r6},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:      "list",
+					MinLength: pointer(2),
+				},
+			},
+			expected: []*api.Record{r5},
+		},
+		"max length array": {
+			records: []*api.Record{r1, r5, r6},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:      "list",
+					MaxLength: pointer(1),
+				},
+			},
+			expected: []*api.Record{r6},
+		},
+		"min and max length string": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:      "value",
+					MinLength: pointer(8),
+					MaxLength: pointer(8),
+				},
+			},
+			expected: []*api.Record{r1, r2},
+		},
+		"min length object": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:      "map",
+					MinLength: pointer(2),
+				},
+			},
+			expected: []*api.Record{r1},
+		},
+		"min length, not a string, array or object": {
+			records: []*api.Record{r1},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:      "numeric",
+					MinLength: pointer(1),
+				},
+			},
+			expectError: true,
+		},
 		"equal strings, inverted": {
 			records: defaultRecords,
 			conditions: []*insights.FilterCondition{
@@ -0,0 +1,107 @@
+package record
+
+import (
+	"fmt"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// FilterConditionFromAPI converts a FilterCondition as used by the plugin API
+// during search into a FilterCondition for record insights.
+//
+// The conversion is lossless. The resulting condition does not share any
+// pointer values with the original one.
+func FilterConditionFromAPI(condition *api.FilterCondition) *FilterCondition {
+	if condition == nil {
+		return nil
+	}
+	return &FilterCondition{
+		Path:          condition.Path,
+		Equals:        condition.Equals,
+		IsNull:        copyPointer(condition.IsNull),
+		StartsWith:    copyPointer(condition.StartsWith),
+		EndsWith:      copyPointer(condition.EndsWith),
+		LikeRegex:     copyPointer(condition.LikeRegex),
+		LessThan:      copyPointer(condition.LessThan),
+		LessEquals:    copyPointer(condition.LessEquals),
+		GreaterThan:   copyPointer(condition.GreaterThan),
+		GreaterEquals: copyPointer(condition.GreaterEquals),
+		After:         copyPointer(condition.After),
+		Since:         copyPointer(condition.Since),
+		Before:        copyPointer(condition.Before),
+		Until:         copyPointer(condition.Until),
+		Invert:        copyPointer(condition.Invert),
+		CaseSensitive: copyPointer(condition.CaseSensitive),
+	}
+}
+
+// FilterConditionsFromAPI is the list variant of FilterConditionFromAPI.
+func FilterConditionsFromAPI(conditions []*api.FilterCondition) []*FilterCondition {
+	if conditions == nil {
+		return nil
+	}
+	result := make([]*FilterCondition, len(conditions))
+	for i, condition := range conditions {
+		result[i] = FilterConditionFromAPI(condition)
+	}
+	return result
+}
+
+// FilterConditionToAPI converts a FilterCondition for record insights into a
+// FilterCondition as used by the plugin API during search.
+//
+// The plugin API does not support the IsType, MinLength and MaxLength
+// criteria. Instead of silently dropping them, an error is returned if any of
+// them is set. The resulting condition does not share any pointer values with
+// the original one.
+func FilterConditionToAPI(condition *FilterCondition) (*api.FilterCondition, error) {
+	if condition == nil {
+		return nil, nil
+	}
+	if condition.IsType != nil || hasFilterLengthCriteria(condition) {
+		return nil, fmt.Errorf("filter condition for path %v uses criteria that are not supported by the plugin API", condition.Path)
+	}
+	return &api.FilterCondition{
+		Path:          condition.Path,
+		Equals:        condition.Equals,
+		IsNull:        copyPointer(condition.IsNull),
+		StartsWith:    copyPointer(condition.StartsWith),
+		EndsWith:      copyPointer(condition.EndsWith),
+		LikeRegex:     copyPointer(condition.LikeRegex),
+		LessThan:      copyPointer(condition.LessThan),
+		LessEquals:    copyPointer(condition.LessEquals),
+		GreaterThan:   copyPointer(condition.GreaterThan),
+		GreaterEquals: copyPointer(condition.GreaterEquals),
+		After:         copyPointer(condition.After),
+		Since:         copyPointer(condition.Since),
+		Before:        copyPointer(condition.Before),
+		Until:         copyPointer(condition.Until),
+		Invert:        copyPointer(condition.Invert),
+		CaseSensitive: copyPointer(condition.CaseSensitive),
+	}, nil
+}
+
+// FilterConditionsToAPI is the list variant of FilterConditionToAPI.
+func FilterConditionsToAPI(conditions []*FilterCondition) ([]*api.FilterCondition, error) {
+	if conditions == nil {
+		return nil, nil
+	}
+	result := make([]*api.FilterCondition, len(conditions))
+	for i, condition := range conditions {
+		converted, err := FilterConditionToAPI(condition)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+// FilterAPI is a variant of Filter that accepts the FilterCondition as used by
+// the plugin API during search.
+//
+// This allows to replay search-time filter conditions against the records of
+// an entity.
+func FilterAPI(records []*api.Record, conditions []*api.FilterCondition) ([]*api.Record, error) {
+	return Filter(records, FilterConditionsFromAPI(conditions))
+}
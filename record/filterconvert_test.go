@@ -0,0 +1,108 @@
+package record_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	insights "github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestFilterConditionConversion(t *testing.T) {
+	apiCondition := &api.FilterCondition{
+		Path:          "value",
+		Equals:        "a",
+		IsNull:        pointer(false),
+		StartsWith:    pointer("a"),
+		EndsWith:      pointer("b"),
+		LikeRegex:     pointer("a.*b"),
+		LessThan:      pointer(1.0),
+		LessEquals:    pointer(2.0),
+		GreaterThan:   pointer(3.0),
+		GreaterEquals: pointer(4.0),
+		After:         pointer(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Since:         pointer(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Before:        pointer(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Until:         pointer(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Invert:        pointer(true),
+		CaseSensitive: pointer(true),
+	}
+
+	converted := insights.FilterConditionFromAPI(apiCondition)
+	assert.Equal(t, &insights.FilterCondition{
+		Path:          "value",
+		Equals:        "a",
+		IsNull:        pointer(false),
+		StartsWith:    pointer("a"),
+		EndsWith:      pointer("b"),
+		LikeRegex:     pointer("a.*b"),
+		LessThan:      pointer(1.0),
+		LessEquals:    pointer(2.0),
+		GreaterThan:   pointer(3.0),
+		GreaterEquals: pointer(4.0),
+		After:         pointer(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Since:         pointer(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Before:        pointer(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Until:         pointer(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Invert:        pointer(true),
+		CaseSensitive: pointer(true),
+	}, converted)
+
+	roundTrip, err := insights.FilterConditionToAPI(converted)
+	require.NoError(t, err)
+	assert.Equal(t, apiCondition, roundTrip)
+
+	assert.Nil(t, insights.FilterConditionFromAPI(nil))
+	assert.Nil(t, insights.FilterConditionsFromAPI(nil))
+	nilCondition, err := insights.FilterConditionToAPI(nil)
+	require.NoError(t, err)
+	assert.Nil(t, nilCondition)
+
+	roundTripList, err := insights.FilterConditionsToAPI(insights.FilterConditionsFromAPI([]*api.FilterCondition{apiCondition}))
+	require.NoError(t, err)
+	assert.Equal(t, []*api.FilterCondition{apiCondition}, roundTripList)
+
+	*converted.StartsWith = "changed"
+	*roundTrip.LessThan = 10
+	assert.Equal(t, "a", *apiCondition.StartsWith)
+	assert.Equal(t, 1.0, *converted.LessThan)
+
+	_, err = insights.FilterConditionToAPI(&insights.FilterCondition{
+		Path:      "value",
+		MinLength: pointer(1),
+	})
+	assert.Error(t, err)
+	_, err = insights.FilterConditionsToAPI([]*insights.FilterCondition{
+		{
+			Path:   "value",
+			IsType: pointer(insights.FilterTypeString),
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestFilterAPI(t *testing.T) {
+	r1 := &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"value": "string A",
+		},
+	}
+	r2 := &api.Record{
+		ID: "r2",
+		Data: map[string]any{
+			"value": "string B",
+		},
+	}
+
+	actual, err := insights.FilterAPI([]*api.Record{r1, r2}, []*api.FilterCondition{
+		{
+			Path:     "value",
+			EndsWith: pointer("b"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.Record{r2}, actual)
+}
@@ -13,6 +13,7 @@ type FrequencyDistributionEntry struct {
 	Frequency   int     `json:"frequency"`
 	Percentage  float64 `json:"percentage"`
 	originalPos int
+	key         string
 }
 
 // FrequencyDistribution returns how often a non-null value for the provided
@@ -26,17 +27,39 @@ type FrequencyDistributionEntry struct {
 //
 // Values with with equal frequency will always be returned in the order of the
 // first occurrence for that value.
-func FrequencyDistribution(records []*api.Record, path string, caseSensitive bool, top int, sortASC bool) ([]*FrequencyDistributionEntry, error) { //nolint:gocognit
+func FrequencyDistribution(records []*api.Record, path string, caseSensitive bool, top int, sortASC bool) ([]*FrequencyDistributionEntry, error) {
+	return FrequencyDistributionCollated(records, path, caseSensitive, top, sortASC, "")
+}
+
+// FrequencyDistributionCollated is a variant of FrequencyDistribution that
+// orders values with equal frequency according to the provided collation
+// instead of their first occurrence.
+//
+// The collation is a BCP 47 language tag as described for SortCriteria. If the
+// collation is empty, then it behaves exactly like FrequencyDistribution.
+func FrequencyDistributionCollated(records []*api.Record, path string, caseSensitive bool, top int, sortASC bool, collation string) ([]*FrequencyDistributionEntry, error) { //nolint:gocognit
 	if top == 0 {
 		return []*FrequencyDistributionEntry{}, nil
 	}
+	compareString, err := collationCompare(collation)
+	if err != nil {
+		return nil, err
+	}
+	tieBreak := func(a, b *FrequencyDistributionEntry) int {
+		return cmp.Compare(a.originalPos, b.originalPos)
+	}
+	if collation != "" {
+		tieBreak = func(a, b *FrequencyDistributionEntry) int {
+			return compareString(a.key, b.key)
+		}
+	}
 	positionMap := map[*api.Record]int{}
 	for i := range records {
 		positionMap[records[i]] = i
 	}
 	entriesMap := make(map[string]*FrequencyDistributionEntry, len(records))
 	counted := 0
-	err := Visit(records, path, func(v any, record *api.Record) error {
+	err = Visit(records, path, func(v any, record *api.Record) error {
 		val, err := validateString(v, caseSensitive)
 		if err != nil {
 			return err
@@ -49,6 +72,7 @@ func FrequencyDistribution(records []*api.Record, path string, caseSensitive boo
 					Frequency:   1,
 					Percentage:  0.0,
 					originalPos: positionMap[record],
+					key:         *val,
 				}
 			} else {
 				entriesMap[*val].Frequency++
@@ -70,14 +94,14 @@ func FrequencyDistribution(records []*api.Record, path string, caseSensitive boo
 	}
 	sortFunc := func(a, b *FrequencyDistributionEntry) int {
 		if a.Frequency == b.Frequency {
-			return cmp.Compare(a.originalPos, b.originalPos)
+			return tieBreak(a, b)
 		}
 		return cmp.Compare(b.Frequency, a.Frequency)
 	}
 	if sortASC {
 		sortFunc = func(a, b *FrequencyDistributionEntry) int {
 			if a.Frequency == b.Frequency {
-				return cmp.Compare(a.originalPos, b.originalPos)
+				return tieBreak(a, b)
 			}
 			return cmp.Compare(a.Frequency, b.Frequency)
 		}
@@ -453,3 +453,58 @@ func TestFrequencyDistribution(t *testing.T) {
 		})
 	}
 }
+
+func TestFrequencyDistributionCollated(t *testing.T) {
+	testRecords := []*api.Record{
+		{ID: "r1", Data: map[string]any{"name": "Öl"}},
+		{ID: "r2", Data: map[string]any{"name": "Zoë"}},
+		{ID: "r3", Data: map[string]any{"name": "Ångström"}},
+		{ID: "r4", Data: map[string]any{"name": "Apfel"}},
+		{ID: "r5", Data: map[string]any{"name": "Apfel"}},
+	}
+
+	cases := map[string]struct {
+		collation   string
+		sortASC     bool
+		expected    []any
+		expectError bool
+	}{
+		"without collation": {
+			collation: "",
+			expected:  []any{"Apfel", "Öl", "Zoë", "Ångström"},
+		},
+		"default collation": {
+			collation: "und",
+			expected:  []any{"Apfel", "Ångström", "Öl", "Zoë"},
+		},
+		"swedish collation": {
+			collation: "sv",
+			expected:  []any{"Apfel", "Zoë", "Ångström", "Öl"},
+		},
+		"swedish collation ASC": {
+			collation: "sv",
+			sortASC:   true,
+			expected:  []any{"Zoë", "Ångström", "Öl", "Apfel"},
+		},
+		"invalid collation": {
+			collation:   "not a language",
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.FrequencyDistributionCollated(testRecords, "name", false, -1, c.sortASC, c.collation)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			values := make([]any, 0, len(actual))
+			for _, entry := range actual {
+				values = append(values, entry.Value)
+			}
+			assert.Equal(t, c.expected, values)
+		})
+	}
+}
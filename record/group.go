@@ -1,9 +1,12 @@
 package record
 
 import (
+	"cmp"
+	"fmt"
 	"strings"
 
 	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
 )
 
 // Group returns a list of record lists where the records have been grouped by
@@ -70,3 +73,150 @@ func groupRecordStringKeys(records []*api.Record, paths []string, caseSensitive
 	}
 	return keys, nil
 }
+
+// GroupSort defines the order of the groups returned by GroupWithKeys.
+type GroupSort string
+
+// Supported group sort orders.
+const (
+	// GroupSortNone keeps the groups in the order of their first occurrence.
+	GroupSortNone GroupSort = ""
+	// GroupSortSize orders the groups by their number of records descending.
+	GroupSortSize GroupSort = "size"
+	// GroupSortKey orders the groups by their keys ascending, comparing the
+	// keys the same way as in Sort using SortTypeAuto. Null keys come last.
+	GroupSortKey GroupSort = "key"
+)
+
+// GroupOptions defines optional behavior of GroupWithKeys.
+type GroupOptions struct {
+	Sort GroupSort `json:"sort,omitempty" yaml:"sort,omitempty"`
+	// DropNull removes the group where all keys are null.
+	DropNull bool `json:"dropNull,omitempty" yaml:"dropNull,omitempty"`
+}
+
+// GroupResult contains the records of a single group together with the key
+// values that formed the group.
+type GroupResult struct {
+	// Keys contains the value for each of the grouping paths in the same
+	// order. The values are taken from the first record of the group and
+	// therefore keep the case of their first occurrence.
+	Keys []any `json:"keys"`
+	// Size is the number of records in the group.
+	Size    int           `json:"size"`
+	Records []*api.Record `json:"records"`
+}
+
+// GroupWithKeys is a variant of Group that also returns the key values and
+// the size of each group.
+//
+// The options may be null, in which case the groups are returned in the order
+// of their first occurrence.
+func GroupWithKeys(records []*api.Record, paths []string, caseSensitive bool, options *GroupOptions) ([]*GroupResult, error) {
+	if options == nil {
+		options = &GroupOptions{}
+	}
+	switch options.Sort {
+	case GroupSortNone, GroupSortSize, GroupSortKey:
+	default:
+		return nil, fmt.Errorf("unsupported group sort %q", options.Sort)
+	}
+
+	groups, err := Group(records, paths, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*GroupResult, 0, len(groups))
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		keys, err := groupKeys(group[0], paths)
+		if err != nil {
+			return nil, err
+		}
+		if options.DropNull && len(paths) != 0 && !slices.ContainsFunc(keys, func(key any) bool { return key != nil }) {
+			continue
+		}
+		result = append(result, &GroupResult{
+			Keys:    keys,
+			Size:    len(group),
+			Records: group,
+		})
+	}
+
+	switch options.Sort {
+	case GroupSortSize:
+		slices.SortStableFunc(result, func(a, b *GroupResult) int {
+			return cmp.Compare(b.Size, a.Size)
+		})
+	case GroupSortKey:
+		err = sortGroupsByKey(result, caseSensitive)
+	}
+	return result, err
+}
+
+// groupKeys returns the values of the record for each of the paths.
+//
+// If a path resolves into multiple values, then the key is a list of these
+// values.
+func groupKeys(record *api.Record, paths []string) ([]any, error) {
+	keys := make([]any, len(paths))
+	for i, path := range paths {
+		values := []any{}
+		err := Visit([]*api.Record{record}, path, func(val any, _ *api.Record) error {
+			if val != nil {
+				values = append(values, val)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch len(values) {
+		case 0:
+		case 1:
+			keys[i] = values[0]
+		default:
+			keys[i] = values
+		}
+	}
+	return keys, nil
+}
+
+func sortGroupsByKey(groups []*GroupResult, caseSensitive bool) error {
+	sortKeys := make(map[*GroupResult][]*sortMixedValue, len(groups))
+	for _, group := range groups {
+		values := make([]*sortMixedValue, len(group.Keys))
+		for i, key := range group.Keys {
+			value, err := toSortMixedValue(key, caseSensitive)
+			if err != nil {
+				return err
+			}
+			values[i] = value
+		}
+		sortKeys[group] = values
+	}
+	slices.SortStableFunc(groups, func(a, b *GroupResult) int {
+		keysA, keysB := sortKeys[a], sortKeys[b]
+		for i := range keysA {
+			if c := compareGroupKey(keysA[i], keysB[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+func compareGroupKey(a, b *sortMixedValue) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return compareMixed(*a, *b, strings.Compare)
+}
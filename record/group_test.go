@@ -138,3 +138,112 @@ func TestGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupWithKeys(t *testing.T) {
+	r1 := &api.Record{ID: "r1", Data: map[string]any{"name": "Bob", "city": "Berlin"}}
+	r2 := &api.Record{ID: "r2", Data: map[string]any{"name": nil, "city": "Berlin"}}
+	r3 := &api.Record{ID: "r3", Data: map[string]any{"name": "alice", "city": "Munich"}}
+	r4 := &api.Record{ID: "r4", Data: map[string]any{"name": "Alice", "city": "Munich"}}
+	r5 := &api.Record{ID: "r5", Data: map[string]any{"name": "ALICE", "city": "Munich"}}
+	r6 := &api.Record{ID: "r6", Data: map[string]any{}}
+	r7 := &api.Record{ID: "r7", Data: map[string]any{"name": "Bob", "city": []any{"Berlin", "Paris"}}}
+	defaultRecords := []*api.Record{r1, r2, r3, r4, r5, r6}
+
+	cases := map[string]struct {
+		records       []*api.Record
+		paths         []string
+		caseSensitive bool
+		options       *insights.GroupOptions
+		expected      []*insights.GroupResult
+		expectError   bool
+	}{
+		"empty records": {
+			records:  []*api.Record{},
+			paths:    []string{"name"},
+			expected: []*insights.GroupResult{},
+		},
+		"empty paths": {
+			records: defaultRecords,
+			paths:   []string{},
+			expected: []*insights.GroupResult{
+				{Keys: []any{}, Size: 6, Records: defaultRecords},
+			},
+		},
+		"keys keep first occurrence": {
+			records: defaultRecords,
+			paths:   []string{"name"},
+			expected: []*insights.GroupResult{
+				{Keys: []any{"Bob"}, Size: 1, Records: []*api.Record{r1}},
+				{Keys: []any{nil}, Size: 2, Records: []*api.Record{r2, r6}},
+				{Keys: []any{"alice"}, Size: 3, Records: []*api.Record{r3, r4, r5}},
+			},
+		},
+		"case sensitive": {
+			records:       defaultRecords,
+			paths:         []string{"name"},
+			caseSensitive: true,
+			options:       &insights.GroupOptions{DropNull: true},
+			expected: []*insights.GroupResult{
+				{Keys: []any{"Bob"}, Size: 1, Records: []*api.Record{r1}},
+				{Keys: []any{"alice"}, Size: 1, Records: []*api.Record{r3}},
+				{Keys: []any{"Alice"}, Size: 1, Records: []*api.Record{r4}},
+				{Keys: []any{"ALICE"}, Size: 1, Records: []*api.Record{r5}},
+			},
+		},
+		"sort by size": {
+			records: defaultRecords,
+			paths:   []string{"name"},
+			options: &insights.GroupOptions{Sort: insights.GroupSortSize},
+			expected: []*insights.GroupResult{
+				{Keys: []any{"alice"}, Size: 3, Records: []*api.Record{r3, r4, r5}},
+				{Keys: []any{nil}, Size: 2, Records: []*api.Record{r2, r6}},
+				{Keys: []any{"Bob"}, Size: 1, Records: []*api.Record{r1}},
+			},
+		},
+		"sort by key": {
+			records: defaultRecords,
+			paths:   []string{"name"},
+			options: &insights.GroupOptions{Sort: insights.GroupSortKey},
+			expected: []*insights.GroupResult{
+				{Keys: []any{"alice"}, Size: 3, Records: []*api.Record{r3, r4, r5}},
+				{Keys: []any{"Bob"}, Size: 1, Records: []*api.Record{r1}},
+				{Keys: []any{nil}, Size: 2, Records: []*api.Record{r2, r6}},
+			},
+		},
+		"drop null group": {
+			records: defaultRecords,
+			paths:   []string{"name", "city"},
+			options: &insights.GroupOptions{Sort: insights.GroupSortKey, DropNull: true},
+			expected: []*insights.GroupResult{
+				{Keys: []any{"alice", "Munich"}, Size: 3, Records: []*api.Record{r3, r4, r5}},
+				{Keys: []any{"Bob", "Berlin"}, Size: 1, Records: []*api.Record{r1}},
+				{Keys: []any{nil, "Berlin"}, Size: 1, Records: []*api.Record{r2}},
+			},
+		},
+		"list values": {
+			records: []*api.Record{r7},
+			paths:   []string{"city"},
+			expected: []*insights.GroupResult{
+				{Keys: []any{[]any{"Berlin", "Paris"}}, Size: 1, Records: []*api.Record{r7}},
+			},
+		},
+		"unsupported sort": {
+			records:     defaultRecords,
+			paths:       []string{"name"},
+			options:     &insights.GroupOptions{Sort: "unknown"},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := insights.GroupWithKeys(c.records, c.paths, c.caseSensitive, c.options)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
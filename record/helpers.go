@@ -3,3 +3,11 @@ package record
 func pointer[T any](v T) *T {
 	return &v
 }
+
+// copyPointer returns a pointer to a copy of the value p points to.
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	return pointer(*p)
+}
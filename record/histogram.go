@@ -0,0 +1,209 @@
+package record
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// BinMethod defines how the bins of a histogram are determined.
+type BinMethod string
+
+// Supported bin methods.
+const (
+	// BinFixedWidth uses bins of BinSpec.Width, aligned to multiples of the
+	// width. Each bin is [k*width, (k+1)*width), so a value always falls into
+	// the same bin, regardless of the other values.
+	BinFixedWidth BinMethod = "fixedWidth"
+	// BinEdges uses the explicitly provided BinSpec.Edges.
+	BinEdges BinMethod = "edges"
+	// BinEqualFrequency uses BinSpec.Count bins, each containing roughly the
+	// same amount of values.
+	BinEqualFrequency BinMethod = "equalFrequency"
+	// BinSturges uses ceil(log2(n)) + 1 bins of equal width.
+	BinSturges BinMethod = "sturges"
+	// BinFreedmanDiaconis uses bins of the width 2 * IQR / cbrt(n). If the
+	// interquartile range is zero, then it behaves like BinSturges.
+	BinFreedmanDiaconis BinMethod = "freedmanDiaconis"
+)
+
+// maxHistogramBins limits the number of bins to protect against unreasonable
+// small widths.
+const maxHistogramBins = 10000
+
+// BinSpec defines the bins of a histogram.
+type BinSpec struct {
+	Method BinMethod `json:"method"`
+	Width  float64   `json:"width,omitempty"`
+	Edges  []float64 `json:"edges,omitempty"`
+	Count  int       `json:"count,omitempty"`
+}
+
+// HistogramEntry represents a single bin of a histogram.
+//
+// A bin contains all values from Lower (inclusive) to Upper (exclusive),
+// except the last bin, which also contains its Upper value.
+type HistogramEntry struct {
+	Lower      float64 `json:"lower"`
+	Upper      float64 `json:"upper"`
+	Frequency  int     `json:"frequency"`
+	Percentage float64 `json:"percentage"`
+}
+
+// Histogram returns how many values of the provided numeric path fall into
+// each of the bins defined by the BinSpec.
+//
+// Bins are ordered ascending and also contain bins without any values.
+// Values outside of explicitly provided edges are ignored and do not count
+// towards the percentage.
+//
+// Using histogram on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns an empty list if all values are null.
+func Histogram(records []*api.Record, path string, spec BinSpec) ([]*HistogramEntry, error) {
+	if err := validateBinSpec(spec); err != nil {
+		return nil, err
+	}
+	numbers, err := collectSortedNumbers(records, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(numbers) == 0 {
+		return []*HistogramEntry{}, nil
+	}
+
+	edges, err := binEdges(numbers, spec)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*HistogramEntry, 0, len(edges)-1)
+	for i := 1; i < len(edges); i++ {
+		result = append(result, &HistogramEntry{
+			Lower: edges[i-1],
+			Upper: edges[i],
+		})
+	}
+
+	counted := 0
+	for _, number := range numbers {
+		i := binIndex(edges, number)
+		if i < 0 {
+			continue
+		}
+		result[i].Frequency++
+		counted++
+	}
+	if counted == 0 {
+		return result, nil
+	}
+	for _, entry := range result {
+		entry.Percentage = float64(entry.Frequency) / float64(counted)
+	}
+	return result, nil
+}
+
+func validateBinSpec(spec BinSpec) error {
+	switch spec.Method {
+	case BinFixedWidth:
+		if spec.Width <= 0 || math.IsInf(spec.Width, 0) || math.IsNaN(spec.Width) {
+			return fmt.Errorf("invalid bin width %v, expected a positive number", spec.Width)
+		}
+	case BinEdges:
+		if len(spec.Edges) < 2 {
+			return fmt.Errorf("invalid bin edges, expected at least two edges")
+		}
+		for i := 1; i < len(spec.Edges); i++ {
+			if spec.Edges[i-1] >= spec.Edges[i] {
+				return fmt.Errorf("invalid bin edges, expected strictly increasing edges")
+			}
+		}
+	case BinEqualFrequency:
+		if spec.Count <= 0 || spec.Count > maxHistogramBins {
+			return fmt.Errorf("invalid bin count %v, expected a value between 1 and %v", spec.Count, maxHistogramBins)
+		}
+	case BinSturges, BinFreedmanDiaconis:
+	default:
+		return fmt.Errorf("unsupported bin method %q", spec.Method)
+	}
+	return nil
+}
+
+func binEdges(sorted []float64, spec BinSpec) ([]float64, error) {
+	minVal := sorted[0]
+	maxVal := sorted[len(sorted)-1]
+	switch spec.Method {
+	case BinFixedWidth:
+		// the upper edge must be above the maximum value, so that every bin is
+		// [k*width, (k+1)*width) independent of the other values
+		start := math.Floor(minVal/spec.Width) * spec.Width
+		count := math.Floor((maxVal-start)/spec.Width) + 1
+		if count > maxHistogramBins {
+			return nil, fmt.Errorf("bin width %v results in more than %v bins", spec.Width, maxHistogramBins)
+		}
+		edges := make([]float64, 0, int(count)+2)
+		for i := 0; i <= int(count); i++ {
+			edges = append(edges, start+float64(i)*spec.Width)
+		}
+		if edges[len(edges)-1] <= maxVal {
+			edges = append(edges, start+(count+1)*spec.Width)
+		}
+		return edges, nil
+	case BinEdges:
+		return spec.Edges, nil
+	case BinEqualFrequency:
+		return equalFrequencyEdges(sorted, spec.Count), nil
+	case BinFreedmanDiaconis:
+		iqr := quantile(sorted, 0.75, QuantileLinear) - quantile(sorted, 0.25, QuantileLinear)
+		if iqr > 0 {
+			width := 2 * iqr / math.Cbrt(float64(len(sorted)))
+			count := math.Min(maxHistogramBins, math.Max(1, math.Ceil((maxVal-minVal)/width)))
+			return equalWidthEdges(minVal, maxVal, int(count)), nil
+		}
+	}
+	if minVal == maxVal {
+		return []float64{minVal, maxVal}, nil
+	}
+	count := math.Ceil(math.Log2(float64(len(sorted)))) + 1
+	return equalWidthEdges(minVal, maxVal, int(count)), nil
+}
+
+func equalWidthEdges(start float64, end float64, count int) []float64 {
+	edges := make([]float64, 0, count+1)
+	width := (end - start) / float64(count)
+	for i := 0; i < count; i++ {
+		edges = append(edges, start+float64(i)*width)
+	}
+	return append(edges, end)
+}
+
+func equalFrequencyEdges(sorted []float64, count int) []float64 {
+	edges := make([]float64, 0, count+1)
+	for i := 0; i <= count; i++ {
+		edge := quantile(sorted, float64(i)/float64(count), QuantileLinear)
+		if len(edges) > 0 && edges[len(edges)-1] >= edge {
+			continue
+		}
+		edges = append(edges, edge)
+	}
+	if len(edges) == 1 {
+		edges = append(edges, edges[0])
+	}
+	return edges
+}
+
+// binIndex returns the index of the bin for the provided value or -1 if the
+// value is outside of all bins.
+func binIndex(edges []float64, value float64) int {
+	last := len(edges) - 1
+	if value < edges[0] || value > edges[last] {
+		return -1
+	}
+	if value == edges[last] {
+		return last - 1
+	}
+	return sort.Search(len(edges), func(i int) bool {
+		return edges[i] > value
+	}) - 1
+}
@@ -0,0 +1,180 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestHistogram(t *testing.T) {
+	testRecords := []*api.Record{
+		{ID: "r1", Data: map[string]any{"num": 1.0}},
+		{ID: "r2", Data: map[string]any{"num": "2"}},
+		{ID: "r3", Data: map[string]any{"num": 2.0}},
+		{ID: "r4", Data: map[string]any{"num": 3.0}},
+		{ID: "r5", Data: map[string]any{"num": 7.0}},
+		{ID: "r6", Data: map[string]any{"num": 9.0}},
+		{ID: "r7", Data: map[string]any{"num": 10.0}},
+		{ID: "r8", Data: map[string]any{}},
+		nil,
+	}
+
+	cases := map[string]struct {
+		records     []*api.Record
+		spec        record.BinSpec
+		expected    []*record.HistogramEntry
+		expectError bool
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			spec:     record.BinSpec{Method: record.BinSturges},
+			expected: []*record.HistogramEntry{},
+		},
+		"fixed width": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinFixedWidth, Width: 5},
+			expected: []*record.HistogramEntry{
+				{Lower: 0, Upper: 5, Frequency: 4, Percentage: 4.0 / 7},
+				{Lower: 5, Upper: 10, Frequency: 2, Percentage: 2.0 / 7},
+				{Lower: 10, Upper: 15, Frequency: 1, Percentage: 1.0 / 7},
+			},
+		},
+		"fixed width with maximum on edge": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": 0.0}},
+				{ID: "r2", Data: map[string]any{"num": 5.0}},
+			},
+			spec: record.BinSpec{Method: record.BinFixedWidth, Width: 5},
+			expected: []*record.HistogramEntry{
+				{Lower: 0, Upper: 5, Frequency: 1, Percentage: 0.5},
+				{Lower: 5, Upper: 10, Frequency: 1, Percentage: 0.5},
+			},
+		},
+		"fixed width with maximum inside bin": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": 0.0}},
+				{ID: "r2", Data: map[string]any{"num": 5.0}},
+				{ID: "r3", Data: map[string]any{"num": 6.0}},
+			},
+			spec: record.BinSpec{Method: record.BinFixedWidth, Width: 5},
+			expected: []*record.HistogramEntry{
+				{Lower: 0, Upper: 5, Frequency: 1, Percentage: 1.0 / 3},
+				{Lower: 5, Upper: 10, Frequency: 2, Percentage: 2.0 / 3},
+			},
+		},
+		"fixed width with single value": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": 12.0}},
+			},
+			spec: record.BinSpec{Method: record.BinFixedWidth, Width: 5},
+			expected: []*record.HistogramEntry{
+				{Lower: 10, Upper: 15, Frequency: 1, Percentage: 1},
+			},
+		},
+		"explicit edges": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinEdges, Edges: []float64{0, 2, 5, 8}},
+			expected: []*record.HistogramEntry{
+				{Lower: 0, Upper: 2, Frequency: 1, Percentage: 0.2},
+				{Lower: 2, Upper: 5, Frequency: 3, Percentage: 0.6},
+				{Lower: 5, Upper: 8, Frequency: 1, Percentage: 0.2},
+			},
+		},
+		"explicit edges without matching values": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinEdges, Edges: []float64{20, 30}},
+			expected: []*record.HistogramEntry{
+				{Lower: 20, Upper: 30, Frequency: 0, Percentage: 0},
+			},
+		},
+		"equal frequency": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinEqualFrequency, Count: 2},
+			expected: []*record.HistogramEntry{
+				{Lower: 1, Upper: 3, Frequency: 3, Percentage: 3.0 / 7},
+				{Lower: 3, Upper: 10, Frequency: 4, Percentage: 4.0 / 7},
+			},
+		},
+		"sturges": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinSturges},
+			expected: []*record.HistogramEntry{
+				{Lower: 1, Upper: 3.25, Frequency: 4, Percentage: 4.0 / 7},
+				{Lower: 3.25, Upper: 5.5, Frequency: 0, Percentage: 0},
+				{Lower: 5.5, Upper: 7.75, Frequency: 1, Percentage: 1.0 / 7},
+				{Lower: 7.75, Upper: 10, Frequency: 2, Percentage: 2.0 / 7},
+			},
+		},
+		"freedman diaconis": {
+			records: testRecords,
+			spec:    record.BinSpec{Method: record.BinFreedmanDiaconis},
+			expected: []*record.HistogramEntry{
+				{Lower: 1, Upper: 5.5, Frequency: 4, Percentage: 4.0 / 7},
+				{Lower: 5.5, Upper: 10, Frequency: 3, Percentage: 3.0 / 7},
+			},
+		},
+		"equal values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": 5.0}},
+				{ID: "r2", Data: map[string]any{"num": 5.0}},
+			},
+			spec: record.BinSpec{Method: record.BinFreedmanDiaconis},
+			expected: []*record.HistogramEntry{
+				{Lower: 5, Upper: 5, Frequency: 2, Percentage: 1},
+			},
+		},
+		"invalid width": {
+			records:     testRecords,
+			spec:        record.BinSpec{Method: record.BinFixedWidth, Width: 0},
+			expectError: true,
+		},
+		"too many bins": {
+			records:     testRecords,
+			spec:        record.BinSpec{Method: record.BinFixedWidth, Width: 0.0001},
+			expectError: true,
+		},
+		"invalid edges": {
+			records:     testRecords,
+			spec:        record.BinSpec{Method: record.BinEdges, Edges: []float64{1, 1}},
+			expectError: true,
+		},
+		"invalid count": {
+			records:     testRecords,
+			spec:        record.BinSpec{Method: record.BinEqualFrequency},
+			expectError: true,
+		},
+		"invalid method": {
+			records:     testRecords,
+			spec:        record.BinSpec{Method: "scott"},
+			expectError: true,
+		},
+		"non numeric values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": "not number"}},
+			},
+			spec:        record.BinSpec{Method: record.BinSturges},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Histogram(c.records, "num", c.spec)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, len(c.expected))
+			for i := range c.expected {
+				assert.InDelta(t, c.expected[i].Lower, actual[i].Lower, 1e-9)
+				assert.InDelta(t, c.expected[i].Upper, actual[i].Upper, 1e-9)
+				assert.Equal(t, c.expected[i].Frequency, actual[i].Frequency)
+				assert.InDelta(t, c.expected[i].Percentage, actual[i].Percentage, 1e-9)
+			}
+		})
+	}
+}
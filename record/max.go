@@ -1,6 +1,8 @@
 package record
 
 import (
+	"time"
+
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
@@ -19,3 +21,39 @@ func Max(records []*api.Record, path string) (*float64, error) {
 	})
 	return maxVal, err
 }
+
+// MaxTime returns the latest value of the provided time path.
+//
+// Using maxTime on non-time paths will raise an error.
+// Returns null if all values are null.
+func MaxTime(records []*api.Record, path string) (*time.Time, error) {
+	var maxVal *time.Time
+	err := VisitTime(records, path, func(t *time.Time, _ *api.Record) error {
+		if t != nil {
+			if maxVal == nil || t.After(*maxVal) {
+				maxVal = t
+			}
+		}
+		return nil
+	})
+	return maxVal, err
+}
+
+// MaxString returns the lexicographically highest value of the provided
+// path.
+//
+// By default, the case of the value is ignored and the value is returned in
+// lower case.
+// Returns null if all values are null.
+func MaxString(records []*api.Record, path string, caseSensitive bool) (*string, error) {
+	var maxVal *string
+	err := VisitString(records, path, caseSensitive, func(s *string, _ *api.Record) error {
+		if s != nil {
+			if maxVal == nil || *s > *maxVal {
+				maxVal = s
+			}
+		}
+		return nil
+	})
+	return maxVal, err
+}
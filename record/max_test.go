@@ -2,8 +2,10 @@ package record_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tilotech/tilores-insights/record"
 	api "github.com/tilotech/tilores-plugin-api"
 )
@@ -119,3 +121,47 @@ func TestMax(t *testing.T) {
 		})
 	}
 }
+
+func TestMaxTime(t *testing.T) {
+	records := []*api.Record{
+		{ID: "r1", Data: map[string]any{"time": "2024-01-01T00:00:00+01:00"}},
+		{ID: "r2", Data: map[string]any{"time": "2024-06-01T00:00:00Z"}},
+		{ID: "r3", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+		{ID: "r4", Data: map[string]any{}},
+		nil,
+	}
+
+	actual, err := record.MaxTime(records, "time")
+	require.NoError(t, err)
+	require.NotNil(t, actual)
+	assert.True(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC).Equal(*actual))
+
+	actual, err = record.MaxTime([]*api.Record{nil}, "time")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	_, err = record.MaxTime([]*api.Record{{ID: "r1", Data: map[string]any{"time": "yesterday"}}}, "time")
+	assert.Error(t, err)
+}
+
+func TestMaxString(t *testing.T) {
+	records := []*api.Record{
+		{ID: "r1", Data: map[string]any{"name": "Bob"}},
+		{ID: "r2", Data: map[string]any{"name": "alice"}},
+		{ID: "r3", Data: map[string]any{"name": "Carol"}},
+		{ID: "r4", Data: map[string]any{}},
+		nil,
+	}
+
+	actual, err := record.MaxString(records, "name", false)
+	require.NoError(t, err)
+	assert.Equal(t, pointer("carol"), actual)
+
+	actual, err = record.MaxString(records, "name", true)
+	require.NoError(t, err)
+	assert.Equal(t, pointer("alice"), actual)
+
+	actual, err = record.MaxString([]*api.Record{nil}, "name", false)
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+}
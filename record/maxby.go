@@ -0,0 +1,22 @@
+package record
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// MaxBy returns the Record for where the provided path has the highest value
+// together with that value.
+//
+// If all values are numeric, they are compared as numbers, otherwise they are
+// compared as strings. By default, the case of the strings is ignored. If
+// withTies is true, all records holding the highest value are returned as well.
+//
+// Using maxBy on paths mixing numbers with non-numeric values will raise an
+// error.
+// Returns null if the list is empty or does not contain records with the
+// provided path.
+func MaxBy(records []*api.Record, path string, caseSensitive bool, withTies bool) (*ExtremeResult, error) {
+	return extremeBy(records, path, caseSensitive, withTies, func(c int) bool {
+		return c > 0
+	})
+}
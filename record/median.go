@@ -12,13 +12,7 @@ import (
 // Null values are ignored in the calculation.
 // Returns null if all values are null.
 func Median(records []*api.Record, path string) (*float64, error) {
-	numbers := []float64{}
-	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
-		if number != nil {
-			numbers = append(numbers, *number)
-		}
-		return nil
-	})
+	numbers, err := collectSortedNumbers(records, path)
 	if err != nil {
 		return nil, err
 	}
@@ -26,9 +20,25 @@ func Median(records []*api.Record, path string) (*float64, error) {
 	if counted == 0 {
 		return nil, nil
 	}
-	sort.Float64s(numbers)
 	if counted%2 == 1 {
 		return pointer(numbers[(counted / 2)]), nil
 	}
 	return pointer((numbers[counted/2] + numbers[(counted/2)-1]) / 2), nil
 }
+
+// collectSortedNumbers returns all non-null values of the provided numeric
+// path in ascending order.
+func collectSortedNumbers(records []*api.Record, path string) ([]float64, error) {
+	numbers := []float64{}
+	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
+		if number != nil {
+			numbers = append(numbers, *number)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Float64s(numbers)
+	return numbers, nil
+}
@@ -1,6 +1,8 @@
 package record
 
 import (
+	"time"
+
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
@@ -20,3 +22,39 @@ func Min(records []*api.Record, path string) (*float64, error) {
 	})
 	return minVal, err
 }
+
+// MinTime returns the earliest value of the provided time path.
+//
+// Using minTime on non-time paths will raise an error.
+// Returns null if all values are null.
+func MinTime(records []*api.Record, path string) (*time.Time, error) {
+	var minVal *time.Time
+	err := VisitTime(records, path, func(t *time.Time, _ *api.Record) error {
+		if t != nil {
+			if minVal == nil || t.Before(*minVal) {
+				minVal = t
+			}
+		}
+		return nil
+	})
+	return minVal, err
+}
+
+// MinString returns the lexicographically lowest value of the provided
+// path.
+//
+// By default, the case of the value is ignored and the value is returned in
+// lower case.
+// Returns null if all values are null.
+func MinString(records []*api.Record, path string, caseSensitive bool) (*string, error) {
+	var minVal *string
+	err := VisitString(records, path, caseSensitive, func(s *string, _ *api.Record) error {
+		if s != nil {
+			if minVal == nil || *s < *minVal {
+				minVal = s
+			}
+		}
+		return nil
+	})
+	return minVal, err
+}
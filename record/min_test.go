@@ -2,8 +2,10 @@ package record_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tilotech/tilores-insights/record"
 	api "github.com/tilotech/tilores-plugin-api"
 )
@@ -96,3 +98,47 @@ func TestMin(t *testing.T) {
 		})
 	}
 }
+
+func TestMinTime(t *testing.T) {
+	records := []*api.Record{
+		{ID: "r1", Data: map[string]any{"time": "2024-01-01T00:00:00+01:00"}},
+		{ID: "r2", Data: map[string]any{"time": "2024-06-01T00:00:00Z"}},
+		{ID: "r3", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+		{ID: "r4", Data: map[string]any{}},
+		nil,
+	}
+
+	actual, err := record.MinTime(records, "time")
+	require.NoError(t, err)
+	require.NotNil(t, actual)
+	assert.True(t, time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC).Equal(*actual))
+
+	actual, err = record.MinTime([]*api.Record{nil}, "time")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	_, err = record.MinTime([]*api.Record{{ID: "r1", Data: map[string]any{"time": "yesterday"}}}, "time")
+	assert.Error(t, err)
+}
+
+func TestMinString(t *testing.T) {
+	records := []*api.Record{
+		{ID: "r1", Data: map[string]any{"name": "Bob"}},
+		{ID: "r2", Data: map[string]any{"name": "alice"}},
+		{ID: "r3", Data: map[string]any{"name": "Carol"}},
+		{ID: "r4", Data: map[string]any{}},
+		nil,
+	}
+
+	actual, err := record.MinString(records, "name", false)
+	require.NoError(t, err)
+	assert.Equal(t, pointer("alice"), actual)
+
+	actual, err = record.MinString(records, "name", true)
+	require.NoError(t, err)
+	assert.Equal(t, pointer("Bob"), actual)
+
+	actual, err = record.MinString([]*api.Record{nil}, "name", false)
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+}
@@ -0,0 +1,95 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestMinBy(t *testing.T) {
+	r1 := &api.Record{ID: "r1", Data: map[string]any{"limit": 500.0, "name": "Bob"}}
+	r2 := &api.Record{ID: "r2", Data: map[string]any{"limit": 200.5, "name": "alice"}}
+	r3 := &api.Record{ID: "r3", Data: map[string]any{"limit": "200.5", "name": "Alice"}}
+	r4 := &api.Record{ID: "r4", Data: map[string]any{"limit": nil}}
+	defaultRecords := []*api.Record{r1, r2, r3, r4, nil}
+	r5 := &api.Record{ID: "r5", Data: map[string]any{"code": "900"}}
+	r6 := &api.Record{ID: "r6", Data: map[string]any{"code": "1000"}}
+	r7 := &api.Record{ID: "r7", Data: map[string]any{"code": "n/a"}}
+	codeRecords := []*api.Record{r5, r6, r7}
+
+	cases := map[string]struct {
+		records       []*api.Record
+		path          string
+		caseSensitive bool
+		withTies      bool
+		expected      *record.ExtremeResult
+		expectError   bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+			path:    "limit",
+		},
+		"list with all nil values": {
+			records: []*api.Record{r4, nil},
+			path:    "limit",
+		},
+		"numeric path": {
+			records:  defaultRecords,
+			path:     "limit",
+			expected: &record.ExtremeResult{Record: r2, Value: 200.5},
+		},
+		"numeric path with ties": {
+			records:  defaultRecords,
+			path:     "limit",
+			withTies: true,
+			expected: &record.ExtremeResult{Record: r2, Value: 200.5, Ties: []*api.Record{r2, r3}},
+		},
+		"string path ignores case": {
+			records:  defaultRecords,
+			path:     "name",
+			expected: &record.ExtremeResult{Record: r2, Value: "alice"},
+		},
+		"string path with ties": {
+			records:  defaultRecords,
+			path:     "name",
+			withTies: true,
+			expected: &record.ExtremeResult{Record: r2, Value: "alice", Ties: []*api.Record{r2, r3}},
+		},
+		"string path case sensitive": {
+			records:       defaultRecords,
+			path:          "name",
+			caseSensitive: true,
+			withTies:      true,
+			expected:      &record.ExtremeResult{Record: r3, Value: "Alice", Ties: []*api.Record{r3}},
+		},
+		"mixed numeric and non-numeric values": {
+			records: []*api.Record{
+				{ID: "r5", Data: map[string]any{"limit": 100.0}},
+				{ID: "r6", Data: map[string]any{"limit": 5000.0}},
+				{ID: "r7", Data: map[string]any{"limit": "n/a"}},
+			},
+			path:        "limit",
+			expectError: true,
+		},
+		"numeric strings mixed with other strings are compared as strings": {
+			records:  codeRecords,
+			path:     "code",
+			expected: &record.ExtremeResult{Record: r6, Value: "1000"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.MinBy(c.records, c.path, c.caseSensitive, c.withTies)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package record
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// ModeResult contains the most common value(s) of a path.
+type ModeResult struct {
+	// Values contains all values that share the highest frequency in the order
+	// of their first occurrence.
+	Values []any `json:"values"`
+	// Frequency is how often each of the values is present.
+	Frequency int `json:"frequency"`
+	// Tie is true if more than one value has the highest frequency.
+	Tie bool `json:"tie"`
+}
+
+// Mode returns the value(s) with the highest frequency for the provided path.
+//
+// Values are compared the same way as in ValuesDistinct, which allows using
+// mode on strings, numbers and JSON objects alike. By default, the case of the
+// value is ignored. The returned values keep the case of their first
+// occurrence.
+//
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func Mode(records []*api.Record, path string, caseSensitive bool) (*ModeResult, error) {
+	values, _, err := collectWeightedValues(records, path, caseSensitive, equalWeight)
+	if err != nil {
+		return nil, err
+	}
+	modes := modeValues(values)
+	if len(modes) == 0 {
+		return nil, nil
+	}
+	result := &ModeResult{
+		Values:    make([]any, 0, len(modes)),
+		Frequency: modes[0].frequency,
+		Tie:       len(modes) > 1,
+	}
+	for _, m := range modes {
+		result.Values = append(result.Values, m.value)
+	}
+	return result, nil
+}
+
+// modeValues returns the values with the highest weight in their original
+// order.
+func modeValues(values []*weightedValue) []*weightedValue {
+	modes := make([]*weightedValue, 0, 1)
+	for _, v := range values {
+		if len(modes) > 0 && v.weight < modes[0].weight {
+			continue
+		}
+		if len(modes) > 0 && v.weight > modes[0].weight {
+			modes = modes[:0]
+		}
+		modes = append(modes, v)
+	}
+	return modes
+}
+
+// pathMajorities returns the single most common value for each of the
+// provided paths, or nil for paths where multiple values share the highest
+// frequency or which have no values at all.
+func pathMajorities(records []*api.Record, paths []string, caseSensitive bool) ([]*weightedValue, error) {
+	majorities := make([]*weightedValue, len(paths))
+	for i, path := range paths {
+		values, _, err := collectWeightedValues(records, path, caseSensitive, equalWeight)
+		if err != nil {
+			return nil, err
+		}
+		if modes := modeValues(values); len(modes) == 1 {
+			majorities[i] = modes[0]
+		}
+	}
+	return majorities, nil
+}
@@ -0,0 +1,107 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestMode(t *testing.T) {
+	cases := map[string]struct {
+		records       []*api.Record
+		path          string
+		caseSensitive bool
+		expected      *record.ModeResult
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			path:     "value",
+			expected: nil,
+		},
+		"list with all nil values": {
+			records:  []*api.Record{nil, {ID: "r1", Data: map[string]any{}}},
+			path:     "value",
+			expected: nil,
+		},
+		"single mode": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"value": "b"}},
+				{ID: "r2", Data: map[string]any{"value": "A"}},
+				{ID: "r3", Data: map[string]any{"value": "a"}},
+				{ID: "r4", Data: map[string]any{"value": nil}},
+				nil,
+			},
+			path: "value",
+			expected: &record.ModeResult{
+				Values:    []any{"A"},
+				Frequency: 2,
+				Tie:       false,
+			},
+		},
+		"tie when case sensitive": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"value": "b"}},
+				{ID: "r2", Data: map[string]any{"value": "A"}},
+				{ID: "r3", Data: map[string]any{"value": "a"}},
+			},
+			path:          "value",
+			caseSensitive: true,
+			expected: &record.ModeResult{
+				Values:    []any{"b", "A", "a"},
+				Frequency: 1,
+				Tie:       true,
+			},
+		},
+		"numbers": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"value": 1.0}},
+				{ID: "r2", Data: map[string]any{"value": 2.0}},
+				{ID: "r3", Data: map[string]any{"value": 2.0}},
+				{ID: "r4", Data: map[string]any{"value": 1.0}},
+				{ID: "r5", Data: map[string]any{"value": 3.0}},
+			},
+			path: "value",
+			expected: &record.ModeResult{
+				Values:    []any{1.0, 2.0},
+				Frequency: 2,
+				Tie:       true,
+			},
+		},
+		"objects": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"value": map[string]any{"a": "x", "b": "y"}}},
+				{ID: "r2", Data: map[string]any{"value": map[string]any{"b": "Y", "a": "x"}}},
+				{ID: "r3", Data: map[string]any{"value": map[string]any{"a": "z"}}},
+			},
+			path: "value",
+			expected: &record.ModeResult{
+				Values:    []any{map[string]any{"a": "x", "b": "y"}},
+				Frequency: 2,
+				Tie:       false,
+			},
+		},
+		"nested array": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"list": []any{"a", "b", "b"}}},
+				{ID: "r2", Data: map[string]any{"list": []any{"a", "b"}}},
+			},
+			path: "list.*",
+			expected: &record.ModeResult{
+				Values:    []any{"b"},
+				Frequency: 3,
+				Tie:       false,
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Mode(c.records, c.path, c.caseSensitive)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
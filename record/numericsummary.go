@@ -0,0 +1,155 @@
+package record
+
+import (
+	"math"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// NumericSummaryResult contains descriptive statistics of a numeric path.
+//
+// All statistics except Count and Nulls are null if there are no values.
+// The sample variants are null if there are less than two values. Skewness
+// and Kurtosis are null if all values are equal.
+type NumericSummaryResult struct {
+	Count int `json:"count"`
+	Nulls int `json:"nulls"`
+
+	Sum  *float64 `json:"sum"`
+	Mean *float64 `json:"mean"`
+	Min  *float64 `json:"min"`
+	Max  *float64 `json:"max"`
+
+	Variance                *float64 `json:"variance"`
+	StandardDeviation       *float64 `json:"standardDeviation"`
+	SampleVariance          *float64 `json:"sampleVariance"`
+	SampleStandardDeviation *float64 `json:"sampleStandardDeviation"`
+
+	// Skewness is the population skewness (Fisher-Pearson coefficient).
+	Skewness *float64 `json:"skewness"`
+	// Kurtosis is the population excess kurtosis, i.e. 0 for a normal
+	// distribution.
+	Kurtosis *float64 `json:"kurtosis"`
+}
+
+// NumericSummary returns descriptive statistics for the provided numeric path.
+//
+// All statistics are calculated in a single traversal using Welford's online
+// algorithm, which is also numerically more stable than summing up squares.
+//
+// Using numericSummary on non-numeric paths will raise an error.
+// Null values are ignored in the calculation, but counted in Nulls.
+func NumericSummary(records []*api.Record, path string) (*NumericSummaryResult, error) {
+	m := &moments{}
+	nulls := 0
+	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
+		if number == nil {
+			nulls++
+			return nil
+		}
+		m.add(*number)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &NumericSummaryResult{
+		Count: m.n,
+		Nulls: nulls,
+	}
+	if m.n == 0 {
+		return summary, nil
+	}
+	summary.Sum = pointer(m.sum.value())
+	summary.Mean = pointer(m.mean)
+	summary.Min = pointer(m.min)
+	summary.Max = pointer(m.max)
+	summary.Variance = m.variance()
+	summary.StandardDeviation = m.standardDeviation()
+	summary.SampleVariance = m.sampleVariance()
+	summary.SampleStandardDeviation = m.sampleStandardDeviation()
+	if m.m2 > 0 {
+		n := float64(m.n)
+		summary.Skewness = pointer(math.Sqrt(n) * m.m3 / math.Pow(m.m2, 1.5))
+		summary.Kurtosis = pointer(n*m.m4/(m.m2*m.m2) - 3)
+	}
+	return summary, nil
+}
+
+// moments collects the central moments of a series of numbers in a single
+// pass.
+type moments struct {
+	n    int
+	sum  compensatedSum
+	min  float64
+	max  float64
+	mean float64
+	m2   float64
+	m3   float64
+	m4   float64
+}
+
+func (m *moments) add(x float64) {
+	if m.n == 0 || x < m.min {
+		m.min = x
+	}
+	if m.n == 0 || x > m.max {
+		m.max = x
+	}
+	n1 := float64(m.n)
+	m.n++
+	n := float64(m.n)
+	m.sum.add(x)
+	delta := x - m.mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+	term1 := delta * deltaN * n1
+	m.mean += deltaN
+	m.m4 += term1*deltaN2*(n*n-3*n+3) + 6*deltaN2*m.m2 - 4*deltaN*m.m3
+	m.m3 += term1*deltaN*(n-2) - 3*deltaN*m.m2
+	m.m2 += term1
+}
+
+func (m *moments) variance() *float64 {
+	if m.n == 0 {
+		return nil
+	}
+	return pointer(m.m2 / float64(m.n))
+}
+
+func (m *moments) sampleVariance() *float64 {
+	if m.n < 2 {
+		return nil
+	}
+	return pointer(m.m2 / float64(m.n-1))
+}
+
+func (m *moments) standardDeviation() *float64 {
+	return sqrtOrNil(m.variance())
+}
+
+func (m *moments) sampleStandardDeviation() *float64 {
+	return sqrtOrNil(m.sampleVariance())
+}
+
+func sqrtOrNil(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	return pointer(math.Sqrt(*v))
+}
+
+func collectMoments(records []*api.Record, path string) (*moments, error) {
+	m := &moments{}
+	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
+		if number != nil {
+			m.add(*number)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,75 @@
+package record_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestNumericSummary(t *testing.T) {
+	values := []any{2.0, "4", 4.0, 4.0, nil, 5.0, 5.0, 7.0, 9.0}
+	records := make([]*api.Record, 0, len(values)+1)
+	for _, v := range values {
+		records = append(records, &api.Record{
+			ID: "someid",
+			Data: map[string]any{
+				"num": v,
+			},
+		})
+	}
+	records = append(records, nil)
+
+	actual, err := record.NumericSummary(records, "num")
+	require.NoError(t, err)
+	assert.Equal(t, 8, actual.Count)
+	assert.Equal(t, 2, actual.Nulls)
+	assertFloatPointer(t, pointer(40.0), actual.Sum)
+	assertFloatPointer(t, pointer(5.0), actual.Mean)
+	assertFloatPointer(t, pointer(2.0), actual.Min)
+	assertFloatPointer(t, pointer(9.0), actual.Max)
+	assertFloatPointer(t, pointer(4.0), actual.Variance)
+	assertFloatPointer(t, pointer(2.0), actual.StandardDeviation)
+	assertFloatPointer(t, pointer(32.0/7), actual.SampleVariance)
+	assertFloatPointer(t, pointer(2.1380899352993947), actual.SampleStandardDeviation)
+	assertFloatPointer(t, pointer(0.65625), actual.Skewness)
+	assertFloatPointer(t, pointer(-0.21875), actual.Kurtosis)
+}
+
+func TestNumericSummaryWithoutValues(t *testing.T) {
+	actual, err := record.NumericSummary([]*api.Record{nil, {ID: "someid", Data: map[string]any{}}}, "num")
+	require.NoError(t, err)
+	assert.Equal(t, &record.NumericSummaryResult{
+		Count: 0,
+		Nulls: 2,
+	}, actual)
+}
+
+func TestNumericSummaryWithEqualValues(t *testing.T) {
+	actual, err := record.NumericSummary([]*api.Record{
+		{ID: "someid", Data: map[string]any{"num": 3.0}},
+	}, "num")
+	require.NoError(t, err)
+	assert.Equal(t, 1, actual.Count)
+	assertFloatPointer(t, pointer(0.0), actual.Variance)
+	assert.Nil(t, actual.SampleVariance)
+	assert.Nil(t, actual.SampleStandardDeviation)
+	assert.Nil(t, actual.Skewness)
+	assert.Nil(t, actual.Kurtosis)
+}
+
+func TestNumericSummaryWithNonNumbers(t *testing.T) {
+	_, err := record.NumericSummary([]*api.Record{
+		{ID: "someid", Data: map[string]any{"num": "not number"}},
+	}, "num")
+	assert.Error(t, err)
+}
+
+func TestNumericSummaryWithOverflow(t *testing.T) {
+	actual, err := record.NumericSummary(numRecords(1e308, 1e308), "num")
+	require.NoError(t, err)
+	assert.Equal(t, pointer(math.Inf(1)), actual.Sum)
+}
@@ -0,0 +1,221 @@
+package record
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// OutlierMethod defines how outliers are detected.
+type OutlierMethod string
+
+// Supported outlier methods.
+const (
+	// OutlierZScore flags values that are more than 3 standard deviations away
+	// from the mean of all other values.
+	//
+	// Leaving out the scored value keeps a single outlier from inflating the
+	// standard deviation, which would otherwise make it impossible to flag
+	// anything in lists of 10 or fewer values. It requires at least 3 values.
+	OutlierZScore OutlierMethod = "zScore"
+	// OutlierModifiedZScore flags values whose modified z-score, based on the
+	// median and the median absolute deviation (MAD), exceeds 3.5.
+	//
+	// If the MAD is zero, the mean absolute deviation scaled by 1.253314 is
+	// used instead.
+	OutlierModifiedZScore OutlierMethod = "modifiedZScore"
+	// OutlierIQR flags values that are more than 1.5 interquartile ranges below
+	// the first or above the third quartile.
+	//
+	// If the interquartile range is zero, all values outside of the quartiles
+	// are flagged.
+	OutlierIQR OutlierMethod = "iqr"
+)
+
+// Thresholds for the absolute score above which a value is an outlier.
+const (
+	outlierZScoreThreshold         = 3.0
+	outlierModifiedZScoreThreshold = 3.5
+	outlierIQRThreshold            = 1.5
+)
+
+// OutlierEntry represents a single value that was flagged as an outlier.
+type OutlierEntry struct {
+	Record *api.Record `json:"record"`
+	Value  float64     `json:"value"`
+	// Score is the signed deviation of the value as calculated by the
+	// OutlierMethod. Negative scores are used for values that are too low.
+	//
+	// For OutlierIQR the score is the distance to the nearest quartile in
+	// multiples of the interquartile range.
+	//
+	// If the reference values do not spread at all, e.g. if all other values
+	// are equal, the score is the plain distance to the reference value.
+	Score float64 `json:"score"`
+}
+
+type numberRecord struct {
+	value  float64
+	record *api.Record
+}
+
+// outlierScorer returns the function that scores a single value and decides
+// whether it is an outlier, or null if the values are not sufficient.
+type outlierScorer func(values []numberRecord) func(x float64) (float64, bool)
+
+// Outliers returns all values of the provided numeric path that are flagged
+// as outliers by the OutlierMethod, together with their records and scores.
+//
+// The entries are ordered by their absolute score descending. A record with
+// multiple values may be returned multiple times.
+//
+// Using outliers on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+func Outliers(records []*api.Record, path string, method OutlierMethod) ([]*OutlierEntry, error) {
+	var scorer outlierScorer
+	switch method {
+	case OutlierZScore:
+		scorer = outlierZScore
+	case OutlierModifiedZScore:
+		scorer = outlierModifiedZScore
+	case OutlierIQR:
+		scorer = outlierIQRScore
+	default:
+		return nil, fmt.Errorf("unsupported outlier method %q", method)
+	}
+
+	values := []numberRecord{}
+	err := VisitNumber(records, path, func(number *float64, record *api.Record) error {
+		if number != nil {
+			values = append(values, numberRecord{value: *number, record: record})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outliers := []*OutlierEntry{}
+	if len(values) == 0 {
+		return outliers, nil
+	}
+	score := scorer(values)
+	if score == nil {
+		return outliers, nil
+	}
+	for _, v := range values {
+		if s, outlier := score(v.value); outlier {
+			outliers = append(outliers, &OutlierEntry{
+				Record: v.record,
+				Value:  v.value,
+				Score:  s,
+			})
+		}
+	}
+	slices.SortStableFunc(outliers, func(a, b *OutlierEntry) int {
+		return cmp.Compare(math.Abs(b.Score), math.Abs(a.Score))
+	})
+	return outliers, nil
+}
+
+func outlierZScore(values []numberRecord) func(x float64) (float64, bool) {
+	n := len(values)
+	if n < 3 {
+		return nil
+	}
+	m := moments{}
+	minCount, maxCount := 0, 0
+	for _, v := range values {
+		m.add(v.value)
+	}
+	for _, v := range values {
+		if v.value == m.min {
+			minCount++
+		}
+		if v.value == m.max {
+			maxCount++
+		}
+	}
+	return func(x float64) (float64, bool) {
+		// mean and sum of squared deviations of all values except x
+		mean := m.mean + (m.mean-x)/float64(n-1)
+		if othersEqual(x, m.min, m.max, minCount, maxCount, n) {
+			return x - mean, x != mean
+		}
+		m2 := max(m.m2-(x-m.mean)*(x-mean), 0)
+		sd := math.Sqrt(m2 / float64(n-2))
+		s := (x - mean) / sd
+		return s, math.Abs(s) > outlierZScoreThreshold
+	}
+}
+
+// othersEqual returns true if all values except x are equal, based on the
+// minimum and maximum values and how often they are present.
+func othersEqual(x, minVal, maxVal float64, minCount, maxCount, n int) bool {
+	switch {
+	case minVal == maxVal:
+		return true
+	case x == minVal && minCount == 1:
+		return maxCount == n-1
+	case x == maxVal && maxCount == 1:
+		return minCount == n-1
+	default:
+		return false
+	}
+}
+
+func outlierModifiedZScore(values []numberRecord) func(x float64) (float64, bool) {
+	sorted := sortedNumbers(values)
+	median := quantile(sorted, 0.5, QuantileLinear)
+	deviations := make([]float64, len(sorted))
+	deviationSum := compensatedSum{}
+	for i, x := range sorted {
+		deviations[i] = math.Abs(x - median)
+		deviationSum.add(deviations[i])
+	}
+	slices.Sort(deviations)
+	scale := quantile(deviations, 0.5, QuantileLinear) / 0.6745
+	if scale == 0 {
+		scale = 1.253314 * deviationSum.value() / float64(len(deviations))
+	}
+	if scale == 0 {
+		return nil
+	}
+	return func(x float64) (float64, bool) {
+		s := (x - median) / scale
+		return s, math.Abs(s) > outlierModifiedZScoreThreshold
+	}
+}
+
+func outlierIQRScore(values []numberRecord) func(x float64) (float64, bool) {
+	sorted := sortedNumbers(values)
+	q1 := quantile(sorted, 0.25, QuantileLinear)
+	q3 := quantile(sorted, 0.75, QuantileLinear)
+	iqr := q3 - q1
+	return func(x float64) (float64, bool) {
+		var distance float64
+		switch {
+		case x > q3:
+			distance = x - q3
+		case x < q1:
+			distance = x - q1
+		}
+		if iqr == 0 {
+			return distance, distance != 0
+		}
+		s := distance / iqr
+		return s, math.Abs(s) > outlierIQRThreshold
+	}
+}
+
+func sortedNumbers(values []numberRecord) []float64 {
+	sorted := make([]float64, len(values))
+	for i, v := range values {
+		sorted[i] = v.value
+	}
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,134 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestOutliers(t *testing.T) {
+	years := []any{1980.0, 1982.0, 1985.0, 1979.0, 1990.0, 1988.0, 1984.0, 1983.0, 1986.0, 1981.0, 1820.0}
+	singleOutlier := numRecords(years...)
+	twoOutliers := numRecords(append(years, 2090.0, nil)...)
+	smallList := numRecords(1980.0, 1982.0, 1979.0, 1981.0, 1820.0)
+	mostlyEqual := numRecords(1980.0, 1980.0, 1980.0, 1980.0, 1820.0)
+	salaries := numRecords(50000.0, 50000.0, 50000.0, 50000.0, 50000000.0)
+
+	cases := map[string]struct {
+		records     []*api.Record
+		method      record.OutlierMethod
+		expected    []*record.OutlierEntry
+		expectError bool
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			method:   record.OutlierZScore,
+			expected: []*record.OutlierEntry{},
+		},
+		"z-score": {
+			records: singleOutlier,
+			method:  record.OutlierZScore,
+			expected: []*record.OutlierEntry{
+				{Record: singleOutlier[10], Value: 1820, Score: (1820 - 1983.8) / 3.521363372331802},
+			},
+		},
+		"z-score with small list": {
+			records: smallList,
+			method:  record.OutlierZScore,
+			expected: []*record.OutlierEntry{
+				{Record: smallList[4], Value: 1820, Score: (1820 - 1980.5) / 1.2909944487358056},
+			},
+		},
+		"z-score with multiple outliers": {
+			records: twoOutliers,
+			method:  record.OutlierZScore,
+			expected: []*record.OutlierEntry{
+				{Record: twoOutliers[10], Value: 1820, Score: (1820 - 21928.0/11) / 32.194296502218016},
+			},
+		},
+		"z-score with too few values": {
+			records:  numRecords(1980.0, 1820.0),
+			method:   record.OutlierZScore,
+			expected: []*record.OutlierEntry{},
+		},
+		"z-score with equal other values": {
+			records: salaries,
+			method:  record.OutlierZScore,
+			expected: []*record.OutlierEntry{
+				{Record: salaries[4], Value: 50000000, Score: 49950000},
+			},
+		},
+		"modified z-score": {
+			records: twoOutliers,
+			method:  record.OutlierModifiedZScore,
+			expected: []*record.OutlierEntry{
+				{Record: twoOutliers[10], Value: 1820, Score: 0.6745 * (1820 - 1983.5) / 3},
+				{Record: twoOutliers[11], Value: 2090, Score: 0.6745 * (2090 - 1983.5) / 3},
+			},
+		},
+		"modified z-score with zero MAD": {
+			records: salaries,
+			method:  record.OutlierModifiedZScore,
+			expected: []*record.OutlierEntry{
+				{Record: salaries[4], Value: 50000000, Score: 49950000 / (1.253314 * 9990000)},
+			},
+		},
+		"modified z-score with mostly equal values": {
+			records: mostlyEqual,
+			method:  record.OutlierModifiedZScore,
+			expected: []*record.OutlierEntry{
+				{Record: mostlyEqual[4], Value: 1820, Score: -160 / (1.253314 * 32)},
+			},
+		},
+		"modified z-score with equal values": {
+			records:  numRecords(1980.0, 1980.0, 1980.0),
+			method:   record.OutlierModifiedZScore,
+			expected: []*record.OutlierEntry{},
+		},
+		"iqr": {
+			records: twoOutliers,
+			method:  record.OutlierIQR,
+			expected: []*record.OutlierEntry{
+				{Record: twoOutliers[10], Value: 1820, Score: (1820 - 1980.75) / 5.75},
+				{Record: twoOutliers[11], Value: 2090, Score: (2090 - 1986.5) / 5.75},
+			},
+		},
+		"iqr with zero range": {
+			records: salaries,
+			method:  record.OutlierIQR,
+			expected: []*record.OutlierEntry{
+				{Record: salaries[4], Value: 50000000, Score: 49950000},
+			},
+		},
+		"unsupported method": {
+			records:     singleOutlier,
+			method:      "unknown",
+			expectError: true,
+		},
+		"non numeric values": {
+			records:     numRecords("not a number"),
+			method:      record.OutlierIQR,
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Outliers(c.records, "num", c.method)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, len(c.expected))
+			for i := range c.expected {
+				assert.Same(t, c.expected[i].Record, actual[i].Record)
+				assert.Equal(t, c.expected[i].Value, actual[i].Value)
+				assert.InDelta(t, c.expected[i].Score, actual[i].Score, 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package record
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// PartitionConditions is a named set of filter conditions used by PartitionBy.
+type PartitionConditions struct {
+	Name       string
+	Conditions []*FilterCondition
+}
+
+// PartitionResult is a named list of records as returned by PartitionBy.
+type PartitionResult struct {
+	Name    string
+	Records []*api.Record
+}
+
+// Partition splits the records into those for which the FilterCondition
+// applies and those for which it does not.
+//
+// It behaves the same as calling Filter twice with the second call using the
+// inverted conditions, but only checks each record once.
+func Partition(records []*api.Record, conditions []*FilterCondition) ([]*api.Record, []*api.Record, error) {
+	if len(conditions) == 0 {
+		return records, []*api.Record{}, nil
+	}
+
+	matching := make([]*api.Record, 0, len(records))
+	rest := make([]*api.Record, 0, len(records))
+
+	for _, record := range records {
+		keep, err := checkFilterConditions(record, conditions)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keep {
+			matching = append(matching, record)
+		} else {
+			rest = append(rest, record)
+		}
+	}
+
+	return matching, rest, nil
+}
+
+// PartitionBy assigns each record to the first PartitionConditions that apply
+// to it.
+//
+// The result contains one entry per provided PartitionConditions in the same
+// order, followed by an entry with the defaultName that contains all records
+// that did not match any of the conditions. Partitions without any records are
+// still returned with an empty list.
+func PartitionBy(records []*api.Record, partitions []*PartitionConditions, defaultName string) ([]*PartitionResult, error) {
+	result := make([]*PartitionResult, 0, len(partitions)+1)
+	for _, partition := range partitions {
+		result = append(result, &PartitionResult{
+			Name:    partition.Name,
+			Records: []*api.Record{},
+		})
+	}
+	defaultPartition := &PartitionResult{
+		Name:    defaultName,
+		Records: []*api.Record{},
+	}
+	result = append(result, defaultPartition)
+
+	for _, record := range records {
+		idx, err := partitionIndex(record, partitions)
+		if err != nil {
+			return nil, err
+		}
+		if idx < 0 {
+			defaultPartition.Records = append(defaultPartition.Records, record)
+			continue
+		}
+		result[idx].Records = append(result[idx].Records, record)
+	}
+
+	return result, nil
+}
+
+func partitionIndex(record *api.Record, partitions []*PartitionConditions) (int, error) {
+	for i, partition := range partitions {
+		keep, err := checkFilterConditions(record, partition.Conditions)
+		if err != nil {
+			return -1, err
+		}
+		if keep {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,227 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	insights "github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestPartition(t *testing.T) {
+	r1 := &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"value":   "a",
+			"numeric": 1.0,
+		},
+	}
+	r2 := &api.Record{
+		ID: "r2",
+		Data: map[string]any{
+			"value":   "b",
+			"numeric": 2.0,
+		},
+	}
+	r3 := &api.Record{
+		ID: "r3",
+		Data: map[string]any{
+			"value":   "A",
+			"numeric": "not a number",
+		},
+	}
+	defaultRecords := []*api.Record{
+		r1, r2, r3,
+	}
+
+	cases := map[string]struct {
+		records          []*api.Record
+		conditions       []*insights.FilterCondition
+		expectedMatching []*api.Record
+		expectedRest     []*api.Record
+		expectError      bool
+	}{
+		"no conditions": {
+			records:          defaultRecords,
+			conditions:       []*insights.FilterCondition{},
+			expectedMatching: defaultRecords,
+			expectedRest:     []*api.Record{},
+		},
+		"empty list": {
+			records: []*api.Record{},
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "value",
+					Equals: "a",
+				},
+			},
+			expectedMatching: []*api.Record{},
+			expectedRest:     []*api.Record{},
+		},
+		"split by equal": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "value",
+					Equals: "a",
+				},
+			},
+			expectedMatching: []*api.Record{r1, r3},
+			expectedRest:     []*api.Record{r2},
+		},
+		"split by inverted equal": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:   "value",
+					Equals: "a",
+					Invert: pointer(true),
+				},
+			},
+			expectedMatching: []*api.Record{r2},
+			expectedRest:     []*api.Record{r1, r3},
+		},
+		"error": {
+			records: defaultRecords,
+			conditions: []*insights.FilterCondition{
+				{
+					Path:     "numeric",
+					LessThan: pointer(2.0),
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			matching, rest, err := insights.Partition(c.records, c.conditions)
+
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expectedMatching, matching)
+			assert.Equal(t, c.expectedRest, rest)
+		})
+	}
+}
+
+func TestPartitionBy(t *testing.T) {
+	r1 := &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"value":   "a",
+			"numeric": 1.0,
+		},
+	}
+	r2 := &api.Record{
+		ID: "r2",
+		Data: map[string]any{
+			"value":   "b",
+			"numeric": 2.0,
+		},
+	}
+	r3 := &api.Record{
+		ID: "r3",
+		Data: map[string]any{
+			"value":   "c",
+			"numeric": 3.0,
+		},
+	}
+	r4 := &api.Record{
+		ID: "r4",
+		Data: map[string]any{
+			"value":   "d",
+			"numeric": "not a number",
+		},
+	}
+	defaultRecords := []*api.Record{
+		r1, r2, r3,
+	}
+
+	cases := map[string]struct {
+		records     []*api.Record
+		partitions  []*insights.PartitionConditions
+		expected    []*insights.PartitionResult
+		expectError bool
+	}{
+		"no partitions": {
+			records:    defaultRecords,
+			partitions: []*insights.PartitionConditions{},
+			expected: []*insights.PartitionResult{
+				{Name: "rest", Records: defaultRecords},
+			},
+		},
+		"first matching partition wins": {
+			records: defaultRecords,
+			partitions: []*insights.PartitionConditions{
+				{
+					Name: "small",
+					Conditions: []*insights.FilterCondition{
+						{
+							Path:       "numeric",
+							LessEquals: pointer(2.0),
+						},
+					},
+				},
+				{
+					Name: "a or b",
+					Conditions: []*insights.FilterCondition{
+						{
+							Path:      "value",
+							LikeRegex: pointer("^[ab]$"),
+						},
+					},
+				},
+				{
+					Name: "empty",
+					Conditions: []*insights.FilterCondition{
+						{
+							Path:   "value",
+							IsNull: pointer(true),
+						},
+					},
+				},
+			},
+			expected: []*insights.PartitionResult{
+				{Name: "small", Records: []*api.Record{r1, r2}},
+				{Name: "a or b", Records: []*api.Record{}},
+				{Name: "empty", Records: []*api.Record{}},
+				{Name: "rest", Records: []*api.Record{r3}},
+			},
+		},
+		"error": {
+			records: []*api.Record{r1, r4},
+			partitions: []*insights.PartitionConditions{
+				{
+					Name: "small",
+					Conditions: []*insights.FilterCondition{
+						{
+							Path:       "numeric",
+							LessEquals: pointer(2.0),
+						},
+					},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := insights.PartitionBy(c.records, c.partitions, "rest")
+
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
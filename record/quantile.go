@@ -0,0 +1,116 @@
+package record
+
+import (
+	"fmt"
+	"math"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// QuantileInterpolation defines how a quantile is calculated if it lies
+// between two values.
+//
+// The methods behave like the ones of the same name in numpy. For the sorted
+// values x of length n and the quantile q, the virtual index is h = (n-1)*q.
+type QuantileInterpolation string
+
+// Supported quantile interpolation methods.
+const (
+	// QuantileLinear returns x[floor(h)] + (h - floor(h)) * (x[ceil(h)] - x[floor(h)]).
+	QuantileLinear QuantileInterpolation = "linear"
+	// QuantileNearest returns x[round(h)], where exact halves are rounded to the
+	// nearest even index.
+	QuantileNearest QuantileInterpolation = "nearest"
+	// QuantileLower returns x[floor(h)].
+	QuantileLower QuantileInterpolation = "lower"
+	// QuantileHigher returns x[ceil(h)].
+	QuantileHigher QuantileInterpolation = "higher"
+)
+
+// Percentile returns the p-th percentile of the provided numeric path.
+//
+// The percentile p must be between 0 and 100. If no interpolation is
+// provided, then QuantileLinear is used.
+//
+// Using percentile on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func Percentile(records []*api.Record, path string, p float64, interpolation QuantileInterpolation) (*float64, error) {
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return nil, fmt.Errorf("invalid percentile %v, expected a value between 0 and 100", p)
+	}
+	quantiles, err := Quantiles(records, path, []float64{p / 100}, interpolation)
+	if err != nil || quantiles == nil {
+		return nil, err
+	}
+	return pointer(quantiles[0]), nil
+}
+
+// Quantiles returns the quantiles of the provided numeric path, one for each
+// of the requested quantiles in the same order.
+//
+// Each quantile must be between 0 and 1. If no interpolation is provided, then
+// QuantileLinear is used.
+//
+// Using quantiles on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func Quantiles(records []*api.Record, path string, qs []float64, interpolation QuantileInterpolation) ([]float64, error) {
+	for _, q := range qs {
+		if q < 0 || q > 1 || math.IsNaN(q) {
+			return nil, fmt.Errorf("invalid quantile %v, expected a value between 0 and 1", q)
+		}
+	}
+	if interpolation == "" {
+		interpolation = QuantileLinear
+	}
+	switch interpolation {
+	case QuantileLinear, QuantileNearest, QuantileLower, QuantileHigher:
+	default:
+		return nil, fmt.Errorf("unsupported quantile interpolation %q", interpolation)
+	}
+
+	numbers, err := collectSortedNumbers(records, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(numbers) == 0 {
+		return nil, nil
+	}
+
+	result := make([]float64, 0, len(qs))
+	for _, q := range qs {
+		result = append(result, quantile(numbers, q, interpolation))
+	}
+	return result, nil
+}
+
+// InterquartileRange returns the difference between the third and the first
+// quartile of the provided numeric path using linear interpolation.
+//
+// Using interquartileRange on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func InterquartileRange(records []*api.Record, path string) (*float64, error) {
+	quartiles, err := Quantiles(records, path, []float64{0.25, 0.75}, QuantileLinear)
+	if err != nil || quartiles == nil {
+		return nil, err
+	}
+	return pointer(quartiles[1] - quartiles[0]), nil
+}
+
+func quantile(sorted []float64, q float64, interpolation QuantileInterpolation) float64 {
+	h := float64(len(sorted)-1) * q
+	lower := int(math.Floor(h))
+	upper := int(math.Ceil(h))
+	switch interpolation {
+	case QuantileNearest:
+		return sorted[int(math.RoundToEven(h))]
+	case QuantileLower:
+		return sorted[lower]
+	case QuantileHigher:
+		return sorted[upper]
+	default:
+		return sorted[lower] + (h-float64(lower))*(sorted[upper]-sorted[lower])
+	}
+}
@@ -0,0 +1,159 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func quantileTestRecords() []*api.Record {
+	return []*api.Record{
+		{ID: "r1", Data: map[string]any{"num": 10.0}},
+		{ID: "r2", Data: map[string]any{"num": "2"}},
+		{ID: "r3", Data: map[string]any{}},
+		{ID: "r4", Data: map[string]any{"num": 4.0}},
+		nil,
+		{ID: "r5", Data: map[string]any{"num": 1.0}},
+		{ID: "r6", Data: map[string]any{"num": 3.0}},
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	cases := map[string]struct {
+		records       []*api.Record
+		p             float64
+		interpolation record.QuantileInterpolation
+		expected      *float64
+		expectError   bool
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			p:        90,
+			expected: nil,
+		},
+		"list with all nil values": {
+			records:  []*api.Record{nil, nil},
+			p:        90,
+			expected: nil,
+		},
+		"default interpolation": {
+			records:  quantileTestRecords(),
+			p:        90,
+			expected: pointer(7.6),
+		},
+		"linear": {
+			records:       quantileTestRecords(),
+			p:             90,
+			interpolation: record.QuantileLinear,
+			expected:      pointer(7.6),
+		},
+		"lower": {
+			records:       quantileTestRecords(),
+			p:             90,
+			interpolation: record.QuantileLower,
+			expected:      pointer(4.0),
+		},
+		"higher": {
+			records:       quantileTestRecords(),
+			p:             90,
+			interpolation: record.QuantileHigher,
+			expected:      pointer(10.0),
+		},
+		"nearest": {
+			records:       quantileTestRecords(),
+			p:             90,
+			interpolation: record.QuantileNearest,
+			expected:      pointer(10.0),
+		},
+		"nearest rounds half to even": {
+			records:       quantileTestRecords(),
+			p:             62.5,
+			interpolation: record.QuantileNearest,
+			expected:      pointer(3.0),
+		},
+		"minimum": {
+			records:  quantileTestRecords(),
+			p:        0,
+			expected: pointer(1.0),
+		},
+		"maximum": {
+			records:  quantileTestRecords(),
+			p:        100,
+			expected: pointer(10.0),
+		},
+		"single value": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": 5.0}},
+			},
+			p:        25,
+			expected: pointer(5.0),
+		},
+		"invalid percentile": {
+			records:     quantileTestRecords(),
+			p:           101,
+			expectError: true,
+		},
+		"invalid interpolation": {
+			records:       quantileTestRecords(),
+			p:             50,
+			interpolation: "midpoint",
+			expectError:   true,
+		},
+		"list with non numbers values causes an error": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"num": "10.0"}},
+				{ID: "r2", Data: map[string]any{"num": "not number"}},
+			},
+			p:           50,
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Percentile(c.records, "num", c.p, c.interpolation)
+			if c.expectError {
+				assert.Error(t, err)
+			} else if c.expected == nil {
+				require.NoError(t, err)
+				assert.Nil(t, actual)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, actual)
+				assert.InDelta(t, *c.expected, *actual, 1e-9)
+			}
+		})
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	actual, err := record.Quantiles(quantileTestRecords(), "num", []float64{0.5, 0.25, 0.9}, record.QuantileLinear)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{3, 2, 7.6}, actual, 1e-9)
+
+	actual, err = record.Quantiles([]*api.Record{}, "num", []float64{0.5}, record.QuantileLinear)
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	_, err = record.Quantiles(quantileTestRecords(), "num", []float64{0.5, -0.1}, record.QuantileLinear)
+	assert.Error(t, err)
+}
+
+func TestInterquartileRange(t *testing.T) {
+	actual, err := record.InterquartileRange(quantileTestRecords(), "num")
+	require.NoError(t, err)
+	require.NotNil(t, actual)
+	assert.Equal(t, 2.0, *actual)
+
+	actual, err = record.InterquartileRange([]*api.Record{nil}, "num")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	_, err = record.InterquartileRange([]*api.Record{
+		{ID: "r1", Data: map[string]any{"num": "not number"}},
+	}, "num")
+	assert.Error(t, err)
+}
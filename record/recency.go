@@ -0,0 +1,101 @@
+package record
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// RecencyWeighting defines how the age of a record affects its weight.
+//
+// The age is determined from the time value of TimePath relative to Reference.
+// If TimePath is empty, then the submit timestamp of the record meta data is
+// used instead. If Reference is not set, then the current time is used.
+// Records without a time value are ignored, times in the future are treated
+// as having no age.
+//
+// Exactly one of HalfLife or Steps must be provided. Using HalfLife, the
+// weight decays exponentially, i.e. a record that is HalfLife old has a weight
+// of 0.5. Using Steps, the weight of the step with the smallest MaxAge that is
+// not exceeded is used, regardless of the order of the steps. Records older
+// than all steps have a weight of 0.
+type RecencyWeighting struct {
+	TimePath  string
+	Reference time.Time
+	HalfLife  time.Duration
+	Steps     []RecencyStep
+}
+
+// RecencyStep defines the weight for records up to a certain age.
+type RecencyStep struct {
+	MaxAge time.Duration
+	Weight float64
+}
+
+// RecencyWeightedConfidence is a variant of Confidence where the contribution
+// of each value is weighted by the age of its record.
+//
+// Using equal weights for all records results in the same value as
+// Confidence.
+//
+// Null values are ignored in the calculation.
+// Returns null if all values are null or if the sum of all weights is zero.
+func RecencyWeightedConfidence(records []*api.Record, path string, caseSensitive bool, weighting RecencyWeighting) (*float64, error) {
+	weight, err := recencyWeight(weighting)
+	if err != nil {
+		return nil, err
+	}
+	return weightedConfidence(records, path, caseSensitive, weight)
+}
+
+func recencyWeight(weighting RecencyWeighting) (func(record *api.Record) (float64, error), error) {
+	if (weighting.HalfLife > 0) == (len(weighting.Steps) > 0) {
+		return nil, fmt.Errorf("invalid recency weighting, expected either a positive half-life or steps")
+	}
+	for _, step := range weighting.Steps {
+		if step.Weight < 0 {
+			return nil, fmt.Errorf("invalid negative weight %v for recency step", step.Weight)
+		}
+		if step.MaxAge < 0 {
+			return nil, fmt.Errorf("invalid negative max age %v for recency step", step.MaxAge)
+		}
+	}
+	steps := slices.Clone(weighting.Steps)
+	slices.SortStableFunc(steps, func(a, b RecencyStep) int {
+		return cmp.Compare(a.MaxAge, b.MaxAge)
+	})
+	reference := weighting.Reference
+	if reference.IsZero() {
+		reference = time.Now()
+	}
+	return func(record *api.Record) (float64, error) {
+		t, err := recordTime(record, weighting.TimePath)
+		if err != nil || t == nil {
+			return 0, err
+		}
+		age := max(reference.Sub(*t), 0)
+		if weighting.HalfLife > 0 {
+			return math.Pow(0.5, float64(age)/float64(weighting.HalfLife)), nil
+		}
+		for _, step := range steps {
+			if age <= step.MaxAge {
+				return step.Weight, nil
+			}
+		}
+		return 0, nil
+	}, nil
+}
+
+func recordTime(record *api.Record, path string) (*time.Time, error) {
+	if path != "" {
+		return ExtractTime(record, path)
+	}
+	if record == nil || record.Meta == nil {
+		return nil, nil
+	}
+	return record.Meta.SubmitTimestamp, nil
+}
@@ -0,0 +1,169 @@
+package record_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestRecencyWeightedConfidence(t *testing.T) {
+	reference := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	withMeta := func(id string, text string, submitted time.Time) *api.Record {
+		return &api.Record{
+			ID: id,
+			Data: map[string]any{
+				"text": text,
+			},
+			Meta: &api.RecordMeta{
+				SubmitTimestamp: &submitted,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		records     []*api.Record
+		weighting   record.RecencyWeighting
+		expected    *float64
+		expectError bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+			weighting: record.RecencyWeighting{
+				Reference: reference,
+				HalfLife:  365 * day,
+			},
+			expected: nil,
+		},
+		"equal ages behave like confidence": {
+			records: []*api.Record{
+				withMeta("r1", "a", reference),
+				withMeta("r2", "a", reference),
+				withMeta("r3", "a", reference),
+				withMeta("r4", "b", reference),
+			},
+			weighting: record.RecencyWeighting{
+				Reference: reference,
+				HalfLife:  365 * day,
+			},
+			expected: pointer(0.625),
+		},
+		"exponential decay using meta data": {
+			records: []*api.Record{
+				withMeta("r1", "a", reference.Add(day)),
+				withMeta("r2", "b", reference.Add(-365*day)),
+				{ID: "r3", Data: map[string]any{"text": "b"}},
+				nil,
+			},
+			weighting: record.RecencyWeighting{
+				Reference: reference,
+				HalfLife:  365 * day,
+			},
+			expected: pointer(5.0 / 9),
+		},
+		"step weights using time path": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a", "time": "2012-01-01T00:00:00Z"}},
+				{ID: "r2", Data: map[string]any{"text": "a", "time": "2013-01-01T00:00:00Z"}},
+				{ID: "r3", Data: map[string]any{"text": "b", "time": "2023-12-31T00:00:00Z"}},
+				{ID: "r4", Data: map[string]any{"text": "b"}},
+				{ID: "r5", Data: map[string]any{"text": "c", "time": "1900-01-01T00:00:00Z"}},
+			},
+			weighting: record.RecencyWeighting{
+				TimePath:  "time",
+				Reference: reference,
+				Steps: []record.RecencyStep{
+					{MaxAge: 365 * day, Weight: 1},
+					{MaxAge: 50 * 365 * day, Weight: 0.1},
+				},
+			},
+			expected: pointer(26.0 / 36),
+		},
+		"unsorted steps": {
+			records: []*api.Record{
+				withMeta("r1", "a", reference.Add(-10*day)),
+				withMeta("r2", "b", reference.Add(-200*day)),
+			},
+			weighting: record.RecencyWeighting{
+				Reference: reference,
+				Steps: []record.RecencyStep{
+					{MaxAge: 365 * day, Weight: 0.5},
+					{MaxAge: 30 * day, Weight: 1},
+				},
+			},
+			expected: pointer(5.0 / 9),
+		},
+		"all weights zero": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a", "time": "2012-01-01T00:00:00Z"}},
+			},
+			weighting: record.RecencyWeighting{
+				TimePath:  "time",
+				Reference: reference,
+				Steps: []record.RecencyStep{
+					{MaxAge: 365 * day, Weight: 1},
+				},
+			},
+			expected: nil,
+		},
+		"half-life and steps": {
+			records: []*api.Record{},
+			weighting: record.RecencyWeighting{
+				HalfLife: day,
+				Steps: []record.RecencyStep{
+					{MaxAge: day, Weight: 1},
+				},
+			},
+			expectError: true,
+		},
+		"neither half-life nor steps": {
+			records:     []*api.Record{},
+			weighting:   record.RecencyWeighting{},
+			expectError: true,
+		},
+		"negative step weight": {
+			records: []*api.Record{},
+			weighting: record.RecencyWeighting{
+				Steps: []record.RecencyStep{
+					{MaxAge: day, Weight: -1},
+				},
+			},
+			expectError: true,
+		},
+		"negative step max age": {
+			records: []*api.Record{},
+			weighting: record.RecencyWeighting{
+				Steps: []record.RecencyStep{
+					{MaxAge: -day, Weight: 1},
+				},
+			},
+			expectError: true,
+		},
+		"invalid time": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"text": "a", "time": "yesterday"}},
+			},
+			weighting: record.RecencyWeighting{
+				TimePath: "time",
+				HalfLife: day,
+			},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.RecencyWeightedConfidence(c.records, "text", false, c.weighting)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assertFloatPointer(t, c.expected, actual)
+		})
+	}
+}
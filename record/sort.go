@@ -1,15 +1,85 @@
 package record
 
 import (
+	"cmp"
+	"fmt"
 	"sort"
+	"strconv"
 
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
+// SortType defines how the values of a path are compared while sorting.
+type SortType string
+
+// Supported sort types.
+//
+// SortTypeAuto compares each value according to its own type. Values of
+// different types are ordered numbers < strings < booleans < arrays < objects.
+// Strings that contain a valid number are treated as numbers. SortTypeNatural
+// compares strings, but treats consecutive digits as a single number, e.g.
+// "item2" is sorted before "item10".
+const (
+	SortTypeAuto    SortType = "auto"
+	SortTypeNumber  SortType = "number"
+	SortTypeTime    SortType = "time"
+	SortTypeString  SortType = "string"
+	SortTypeNatural SortType = "natural"
+)
+
+// SortNulls defines where null values are placed while sorting.
+type SortNulls string
+
+// Supported null placements.
+//
+// SortNullsAuto places null values last when sorting ascending and first when
+// sorting descending.
+const (
+	SortNullsAuto  SortNulls = "auto"
+	SortNullsFirst SortNulls = "first"
+	SortNullsLast  SortNulls = "last"
+)
+
 // SortCriteria defines how to sort.
+//
+// If no Type is provided, then SortTypeAuto is used. If no Nulls is provided,
+// then SortNullsAuto is used. String comparison ignores the case unless
+// CaseSensitive is set.
+//
+// Collation is a BCP 47 language tag, e.g. "de" or "sv", that defines the
+// order of strings according to the rules of that language. Use "und" for the
+// Unicode default collation order. Without a collation, strings are compared
+// byte-wise.
 type SortCriteria struct {
-	Path string
-	ASC  bool
+	Path          string    `json:"path" yaml:"path"`
+	ASC           bool      `json:"asc,omitempty" yaml:"asc,omitempty"`
+	Type          SortType  `json:"type,omitempty" yaml:"type,omitempty"`
+	Nulls         SortNulls `json:"nulls,omitempty" yaml:"nulls,omitempty"`
+	CaseSensitive bool      `json:"caseSensitive,omitempty" yaml:"caseSensitive,omitempty"`
+	Collation     string    `json:"collation,omitempty" yaml:"collation,omitempty"`
+}
+
+// Validate checks the SortCriteria for invalid settings.
+//
+// The returned error is a *ValidationError that refers to the offending field.
+func (c *SortCriteria) Validate() error {
+	if c.Path == "" {
+		return &ValidationError{Field: "path", Message: "must not be empty"}
+	}
+	switch c.Type {
+	case "", SortTypeAuto, SortTypeNumber, SortTypeTime, SortTypeString, SortTypeNatural:
+	default:
+		return &ValidationError{Field: "type", Message: fmt.Sprintf("unsupported sort type %q", c.Type)}
+	}
+	switch c.Nulls {
+	case "", SortNullsAuto, SortNullsFirst, SortNullsLast:
+	default:
+		return &ValidationError{Field: "nulls", Message: fmt.Sprintf("unsupported null placement %q", c.Nulls)}
+	}
+	if _, err := collationCompare(c.Collation); err != nil {
+		return &ValidationError{Field: "collation", Message: err.Error()}
+	}
+	return nil
 }
 
 // Sort returns a new RecordInsights that contains the records ordered by the
@@ -30,25 +100,7 @@ func Sort(records []*api.Record, criteria []*SortCriteria) ([]*api.Record, error
 	}
 
 	sort.SliceStable(sortedIdx, func(i, j int) bool {
-		i2 := sortedIdx[i]
-		j2 := sortedIdx[j]
-		for k := range criteria {
-			if data[k].useNumber {
-				a := data[k].numberValues[i2]
-				b := data[k].numberValues[j2]
-				if sortIsEqual(a, b) {
-					continue
-				}
-				return sortLess(a, b, criteria[k].ASC)
-			}
-			a := data[k].stringValues[i2]
-			b := data[k].stringValues[j2]
-			if sortIsEqual(a, b) {
-				continue
-			}
-			return sortLess(a, b, criteria[k].ASC)
-		}
-		return false
+		return sortRecordLess(data, criteria, sortedIdx[i], sortedIdx[j])
 	})
 
 	sortedRecords := make([]*api.Record, len(records))
@@ -59,58 +111,208 @@ func Sort(records []*api.Record, criteria []*SortCriteria) ([]*api.Record, error
 	return sortedRecords, nil
 }
 
+// sortRecordLess reports whether the record at position i must be sorted
+// before the record at position j.
+func sortRecordLess(data []sortValues, criteria []*SortCriteria, i, j int) bool {
+	for k := range criteria {
+		a := data[k].value(i)
+		b := data[k].value(j)
+		if sortIsEqual(a, b, data[k].compare) {
+			continue
+		}
+		return sortLess(a, b, data[k].compare, criteria[k])
+	}
+	return false
+}
+
 func sortCollectData(records []*api.Record, criteria []*SortCriteria) ([]sortValues, error) {
 	data := make([]sortValues, 0, len(criteria))
-	for range criteria {
-		data = append(data, sortValues{
-			useNumber:    true,
-			numberValues: make([]*float64, 0, len(records)),
-			stringValues: make([]*string, 0, len(records)),
-		})
-	}
-
-	for _, record := range records {
-		for i, c := range criteria {
-			if data[i].useNumber {
-				val, err := ExtractNumber(record, c.Path)
-				if err != nil {
-					data[i].useNumber = false
-				} else {
-					data[i].numberValues = append(data[i].numberValues, val)
-				}
-			}
-			val, err := ExtractString(record, c.Path, false)
-			if err != nil {
+	for _, c := range criteria {
+		values, err := sortCollectCriteria(records, c)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, values)
+	}
+	return data, nil
+}
+
+func sortCollectCriteria(records []*api.Record, c *SortCriteria) (sortValues, error) {
+	values, err := sortLazyCriteria(records, c)
+	if err != nil {
+		return sortValues{}, err
+	}
+	for i := range records {
+		values.value(i)
+		if values.err != nil {
+			return sortValues{}, values.err
+		}
+	}
+	return values, nil
+}
+
+// sortLazyCriteria returns the sortValues for the criteria, which extract the
+// value of a record only once it is requested.
+func sortLazyCriteria(records []*api.Record, c *SortCriteria) (sortValues, error) {
+	compareString, err := collationCompare(c.Collation)
+	if err != nil {
+		return sortValues{}, err
+	}
+	switch c.Type {
+	case "", SortTypeAuto:
+		return sortLazy(records, c, sortExtractMixed(c), func(a, b sortMixedValue) int {
+			return compareMixed(a, b, compareString)
+		}), nil
+	case SortTypeNumber:
+		return sortLazy(records, c, ExtractNumber, cmp.Compare[float64]), nil
+	case SortTypeTime:
+		return sortLazy(records, c, ExtractTime, compareTime), nil
+	case SortTypeString:
+		return sortLazy(records, c, sortExtractString(c), compareString), nil
+	case SortTypeNatural:
+		return sortLazy(records, c, sortExtractString(c), func(a, b string) int {
+			return compareNatural(a, b, compareString)
+		}), nil
+	}
+	return sortValues{}, fmt.Errorf("unsupported sort type %q for path %v", c.Type, c.Path)
+}
+
+func sortLazy[T any](records []*api.Record, c *SortCriteria, extract func(*api.Record, string) (*T, error), compare func(a, b T) int) sortValues {
+	return sortValues{
+		values: make([]any, len(records)),
+		loaded: make([]bool, len(records)),
+		extract: func(i int) (any, error) {
+			val, err := extract(records[i], c.Path)
+			if err != nil || val == nil {
 				return nil, err
 			}
-			data[i].stringValues = append(data[i].stringValues, val)
+			return *val, nil
+		},
+		compare: func(a, b any) int {
+			return compare(a.(T), b.(T))
+		},
+	}
+}
+
+func sortExtractString(c *SortCriteria) func(*api.Record, string) (*string, error) {
+	return func(record *api.Record, path string) (*string, error) {
+		return ExtractString(record, path, c.CaseSensitive)
+	}
+}
+
+// sortMixedValue is a value of any type as used by SortTypeAuto.
+type sortMixedValue struct {
+	rank   int
+	number float64
+	text   string
+}
+
+// Ranks of the different value types for SortTypeAuto.
+const (
+	sortRankNumber = iota
+	sortRankString
+	sortRankBool
+	sortRankArray
+	sortRankObject
+)
+
+func sortExtractMixed(c *SortCriteria) func(*api.Record, string) (*sortMixedValue, error) {
+	return func(record *api.Record, path string) (*sortMixedValue, error) {
+		return toSortMixedValue(Extract(record, path), c.CaseSensitive)
+	}
+}
+
+func toSortMixedValue(val any, caseSensitive bool) (*sortMixedValue, error) {
+	if val == nil {
+		return nil, nil
+	}
+	if b, ok := val.(bool); ok {
+		value := sortMixedValue{rank: sortRankBool}
+		if b {
+			value.number = 1
 		}
+		return &value, nil
+	}
+	text, err := valueToString(val, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	switch valueTypeName(val) {
+	case FilterTypeArray:
+		return &sortMixedValue{rank: sortRankArray, text: *text}, nil
+	case FilterTypeObject:
+		return &sortMixedValue{rank: sortRankObject, text: *text}, nil
+	}
+	if number, err := strconv.ParseFloat(*text, 64); err == nil {
+		return &sortMixedValue{rank: sortRankNumber, number: number}, nil
+	}
+	return &sortMixedValue{rank: sortRankString, text: *text}, nil
+}
+
+func compareMixed(a, b sortMixedValue, compareString func(a, b string) int) int {
+	if c := cmp.Compare(a.rank, b.rank); c != 0 {
+		return c
+	}
+	switch a.rank {
+	case sortRankNumber, sortRankBool:
+		return cmp.Compare(a.number, b.number)
+	default:
+		return compareString(a.text, b.text)
 	}
-	return data, nil
 }
 
-func sortIsEqual[T float64 | string](a, b *T) bool {
+func sortIsEqual(a, b any, compare func(a, b any) int) bool {
 	if a == nil {
 		return b == nil
 	}
-	return b != nil && *a == *b
+	return b != nil && compare(a, b) == 0
 }
 
-func sortLess[T float64 | string](a, b *T, sortASC bool) bool {
+func sortLess(a, b any, compare func(a, b any) int, c *SortCriteria) bool {
 	if a == nil {
-		return !sortASC
+		return sortNullsFirst(c)
 	}
 	if b == nil {
-		return sortASC
+		return !sortNullsFirst(c)
 	}
-	if sortASC {
-		return *a < *b
+	if c.ASC {
+		return compare(a, b) < 0
 	}
-	return *a > *b
+	return compare(a, b) > 0
 }
 
+func sortNullsFirst(c *SortCriteria) bool {
+	switch c.Nulls {
+	case SortNullsFirst:
+		return true
+	case SortNullsLast:
+		return false
+	default:
+		return !c.ASC
+	}
+}
+
+// sortValues contains the extracted values of all records for a single
+// SortCriteria. Null values are represented by nil.
+//
+// The values are extracted on first access. The first extraction error is kept
+// in err and the value is treated as null.
 type sortValues struct {
-	useNumber    bool
-	numberValues []*float64
-	stringValues []*string
+	values  []any
+	loaded  []bool
+	extract func(i int) (any, error)
+	compare func(a, b any) int
+	err     error
+}
+
+func (v *sortValues) value(i int) any {
+	if !v.loaded[i] {
+		val, err := v.extract(i)
+		if err != nil && v.err == nil {
+			v.err = err
+		}
+		v.values[i] = val
+		v.loaded[i] = true
+	}
+	return v.values[i]
 }
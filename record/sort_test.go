@@ -49,11 +49,82 @@ func TestSort(t *testing.T) {
 	defaultRecords := []*api.Record{
 		r1, r2, r3, r4,
 	}
+	r5 := &api.Record{
+		ID: "r5",
+		Data: map[string]any{
+			"time": "2024-01-01T10:00:00+02:00",
+			"item": "item10",
+		},
+	}
+	r6 := &api.Record{
+		ID: "r6",
+		Data: map[string]any{
+			"time": "2024-01-01T09:00:00Z",
+			"item": "item2",
+		},
+	}
+	r7 := &api.Record{
+		ID: "r7",
+		Data: map[string]any{
+			"time": "2024-01-01T08:30:00Z",
+			"item": "Item02",
+		},
+	}
+	r8 := &api.Record{
+		ID: "r8",
+		Data: map[string]any{
+			"time": nil,
+			"item": "item",
+		},
+	}
+	typedRecords := []*api.Record{
+		r5, r6, r7, r8,
+	}
+	r9 := &api.Record{
+		ID: "r9",
+		Data: map[string]any{
+			"name": "Zoë",
+		},
+	}
+	r10 := &api.Record{
+		ID: "r10",
+		Data: map[string]any{
+			"name": "Ångström",
+		},
+	}
+	r11 := &api.Record{
+		ID: "r11",
+		Data: map[string]any{
+			"name": "Apfel",
+		},
+	}
+	r12 := &api.Record{
+		ID: "r12",
+		Data: map[string]any{
+			"name": "Öl",
+		},
+	}
+	localizedRecords := []*api.Record{
+		r9, r10, r11, r12,
+	}
+	r13 := &api.Record{ID: "r13", Data: map[string]any{"value": 10.0}}
+	r14 := &api.Record{ID: "r14", Data: map[string]any{"value": 9}}
+	r15 := &api.Record{ID: "r15", Data: map[string]any{"value": "n/a"}}
+	r16 := &api.Record{ID: "r16", Data: map[string]any{"value": true}}
+	r17 := &api.Record{ID: "r17", Data: map[string]any{"value": false}}
+	r18 := &api.Record{ID: "r18", Data: map[string]any{"value": map[string]any{"a": 1.0}}}
+	r19 := &api.Record{ID: "r19", Data: map[string]any{"value": []any{"b"}}}
+	r20 := &api.Record{ID: "r20", Data: map[string]any{"value": "A"}}
+	r21 := &api.Record{ID: "r21", Data: map[string]any{"value": "8.5"}}
+	mixedRecords := []*api.Record{
+		r13, r14, r15, r16, r17, r18, r19, r20, r21,
+	}
 
 	cases := map[string]struct {
-		records  []*api.Record
-		criteria []*insights.SortCriteria
-		expected []*api.Record
+		records     []*api.Record
+		criteria    []*insights.SortCriteria
+		expected    []*api.Record
+		expectError bool
 	}{
 		"no criteria": {
 			records:  defaultRecords,
@@ -122,13 +193,259 @@ func TestSort(t *testing.T) {
 					ASC:  true,
 				},
 			},
-			expected: []*api.Record{r2, r3, r1, r4},
+			expected: []*api.Record{r2, r1, r3, r4},
+		},
+		"sort by time ASC": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "time",
+					ASC:  true,
+					Type: insights.SortTypeTime,
+				},
+			},
+			expected: []*api.Record{r5, r7, r6, r8},
+		},
+		"sort by time DESC": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "time",
+					ASC:  false,
+					Type: insights.SortTypeTime,
+				},
+			},
+			expected: []*api.Record{r8, r6, r7, r5},
+		},
+		"sort by time, not a time": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "string",
+					ASC:  true,
+					Type: insights.SortTypeTime,
+				},
+			},
+			expectError: true,
+		},
+		"sort by string type": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "item",
+					ASC:  true,
+					Type: insights.SortTypeString,
+				},
+			},
+			expected: []*api.Record{r8, r7, r5, r6},
+		},
+		"sort by natural ASC": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "item",
+					ASC:  true,
+					Type: insights.SortTypeNatural,
+				},
+			},
+			expected: []*api.Record{r8, r6, r7, r5},
+		},
+		"sort by natural DESC": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "item",
+					ASC:  false,
+					Type: insights.SortTypeNatural,
+				},
+			},
+			expected: []*api.Record{r5, r7, r6, r8},
+		},
+		"sort by number type": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "number",
+					ASC:  true,
+					Type: insights.SortTypeNumber,
+				},
+			},
+			expected: []*api.Record{r4, r2, r3, r1},
+		},
+		"sort by number type, not a number": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "mixed",
+					ASC:  true,
+					Type: insights.SortTypeNumber,
+				},
+			},
+			expectError: true,
+		},
+		"sort by time ASC, nulls first": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:  "time",
+					ASC:   true,
+					Type:  insights.SortTypeTime,
+					Nulls: insights.SortNullsFirst,
+				},
+			},
+			expected: []*api.Record{r8, r5, r7, r6},
+		},
+		"sort by time DESC, nulls last": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:  "time",
+					ASC:   false,
+					Type:  insights.SortTypeTime,
+					Nulls: insights.SortNullsLast,
+				},
+			},
+			expected: []*api.Record{r6, r7, r5, r8},
+		},
+		"sort by string DESC, nulls last": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:  "string",
+					ASC:   false,
+					Nulls: insights.SortNullsLast,
+				},
+			},
+			expected: []*api.Record{r2, r4, r1, r3},
+		},
+		"sort by string type, case sensitive": {
+			records: typedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:          "item",
+					ASC:           true,
+					Type:          insights.SortTypeString,
+					CaseSensitive: true,
+				},
+			},
+			expected: []*api.Record{r7, r8, r5, r6},
+		},
+		"sort by string without collation": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "name",
+					ASC:  true,
+				},
+			},
+			expected: []*api.Record{r11, r9, r10, r12},
+		},
+		"sort by string with default collation": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:      "name",
+					ASC:       true,
+					Collation: "und",
+				},
+			},
+			expected: []*api.Record{r10, r11, r12, r9},
+		},
+		"sort by string with german collation": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:      "name",
+					ASC:       true,
+					Type:      insights.SortTypeString,
+					Collation: "de",
+				},
+			},
+			expected: []*api.Record{r10, r11, r12, r9},
+		},
+		"sort by string with swedish collation": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:      "name",
+					ASC:       true,
+					Type:      insights.SortTypeString,
+					Collation: "sv",
+				},
+			},
+			expected: []*api.Record{r11, r9, r10, r12},
+		},
+		"sort by natural with swedish collation DESC": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:      "name",
+					ASC:       false,
+					Type:      insights.SortTypeNatural,
+					Collation: "sv",
+				},
+			},
+			expected: []*api.Record{r12, r10, r9, r11},
+		},
+		"sort by invalid collation": {
+			records: localizedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path:      "name",
+					Collation: "not a language",
+				},
+			},
+			expectError: true,
+		},
+		"sort by mixed types ASC": {
+			records: mixedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "value",
+					ASC:  true,
+				},
+			},
+			expected: []*api.Record{r21, r14, r13, r20, r15, r17, r16, r19, r18},
+		},
+		"sort by mixed types DESC": {
+			records: mixedRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "value",
+					ASC:  false,
+				},
+			},
+			expected: []*api.Record{r18, r19, r16, r17, r15, r20, r13, r14, r21},
+		},
+		"sort by mixed numbers and strings": {
+			records: []*api.Record{r15, r13, r14},
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "value",
+					ASC:  true,
+				},
+			},
+			expected: []*api.Record{r14, r13, r15},
+		},
+		"sort by invalid type": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "number",
+					Type: "unknown",
+				},
+			},
+			expectError: true,
 		},
 	}
 
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
 			actual, err := insights.Sort(c.records, c.criteria)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
 			require.NoError(t, err)
 			assert.Equal(t, c.expected, actual)
 		})
@@ -0,0 +1,158 @@
+package record
+
+import (
+	"cmp"
+	"fmt"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// SourceWeighting defines how much each record counts depending on its
+// source.
+//
+// The source of a record is the case-sensitive value of SourcePath. Records
+// with a source that is not listed in Weights, including records without a
+// source, use the DefaultWeight, which is 1 if not provided. Weights must not
+// be negative.
+type SourceWeighting struct {
+	SourcePath    string
+	Weights       map[string]float64
+	DefaultWeight *float64
+}
+
+// WeightedFrequencyDistributionEntry represents a single row of a weighted
+// frequency distribution table.
+//
+// Frequency is the number of occurrences of the value, while Weight is the sum
+// of the weights of these occurrences. Percentage is the share of the weight.
+type WeightedFrequencyDistributionEntry struct {
+	Value      any     `json:"value"`
+	Frequency  int     `json:"frequency"`
+	Weight     float64 `json:"weight"`
+	Percentage float64 `json:"percentage"`
+}
+
+// WeightedModeResult contains the value(s) with the highest weight of a path.
+type WeightedModeResult struct {
+	// Values contains all values that share the highest weight in the order
+	// of their first occurrence.
+	Values []any `json:"values"`
+	// Weight is the summed up weight of each of the values.
+	Weight float64 `json:"weight"`
+	// Tie is true if more than one value has the highest weight.
+	Tie bool `json:"tie"`
+}
+
+// SourceWeightedConfidence is a variant of Confidence where each value counts
+// with the weight of its records source.
+//
+// Null values are ignored in the calculation.
+// Returns null if all values are null or if the sum of all weights is zero.
+func SourceWeightedConfidence(records []*api.Record, path string, caseSensitive bool, weighting SourceWeighting) (*float64, error) {
+	weight, err := sourceWeight(weighting)
+	if err != nil {
+		return nil, err
+	}
+	return weightedConfidence(records, path, caseSensitive, weight)
+}
+
+// SourceWeightedFrequencyDistribution is a variant of FrequencyDistribution
+// where each value counts with the weight of its records source.
+//
+// The results are ordered by their weight and the options 'top' and 'sortASC'
+// behave the same as in FrequencyDistribution. Values with equal weight will
+// always be returned in the order of the first occurrence for that value.
+func SourceWeightedFrequencyDistribution(records []*api.Record, path string, caseSensitive bool, top int, sortASC bool, weighting SourceWeighting) ([]*WeightedFrequencyDistributionEntry, error) {
+	if top == 0 {
+		return []*WeightedFrequencyDistributionEntry{}, nil
+	}
+	weight, err := sourceWeight(weighting)
+	if err != nil {
+		return nil, err
+	}
+	values, total, err := collectWeightedValues(records, path, caseSensitive, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*WeightedFrequencyDistributionEntry, 0, len(values))
+	for _, v := range values {
+		entry := &WeightedFrequencyDistributionEntry{
+			Value:     v.value,
+			Frequency: v.frequency,
+			Weight:    v.weight,
+		}
+		if total > 0 {
+			entry.Percentage = v.weight / total
+		}
+		result = append(result, entry)
+	}
+	slices.SortStableFunc(result, func(a, b *WeightedFrequencyDistributionEntry) int {
+		if sortASC {
+			return cmp.Compare(a.Weight, b.Weight)
+		}
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+	if top > 0 && top < len(result) {
+		return result[0:top], nil
+	}
+	return result, nil
+}
+
+// SourceWeightedMode is a variant of Mode where each value counts with the
+// weight of its records source.
+//
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func SourceWeightedMode(records []*api.Record, path string, caseSensitive bool, weighting SourceWeighting) (*WeightedModeResult, error) {
+	weight, err := sourceWeight(weighting)
+	if err != nil {
+		return nil, err
+	}
+	values, _, err := collectWeightedValues(records, path, caseSensitive, weight)
+	if err != nil {
+		return nil, err
+	}
+	modes := modeValues(values)
+	if len(modes) == 0 {
+		return nil, nil
+	}
+	result := &WeightedModeResult{
+		Values: make([]any, 0, len(modes)),
+		Weight: modes[0].weight,
+		Tie:    len(modes) > 1,
+	}
+	for _, m := range modes {
+		result.Values = append(result.Values, m.value)
+	}
+	return result, nil
+}
+
+func sourceWeight(weighting SourceWeighting) (func(record *api.Record) (float64, error), error) {
+	defaultWeight := 1.0
+	if weighting.DefaultWeight != nil {
+		defaultWeight = *weighting.DefaultWeight
+	}
+	if defaultWeight < 0 {
+		return nil, fmt.Errorf("invalid negative default weight %v", defaultWeight)
+	}
+	for source, w := range weighting.Weights {
+		if w < 0 {
+			return nil, fmt.Errorf("invalid negative weight %v for source %v", w, source)
+		}
+	}
+	return func(record *api.Record) (float64, error) {
+		source, err := ExtractString(record, weighting.SourcePath, true)
+		if err != nil {
+			return 0, err
+		}
+		if source == nil {
+			return defaultWeight, nil
+		}
+		if w, ok := weighting.Weights[*source]; ok {
+			return w, nil
+		}
+		return defaultWeight, nil
+	}, nil
+}
@@ -0,0 +1,124 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func sourceTestRecords() []*api.Record {
+	return []*api.Record{
+		{ID: "r1", Data: map[string]any{"source": "crm", "name": "Alice", "city": "Berlin"}},
+		{ID: "r2", Data: map[string]any{"source": "crm", "name": "alice", "city": "Berlin"}},
+		{ID: "r3", Data: map[string]any{"source": "web", "name": "Alicia", "city": "Munich"}},
+		{ID: "r4", Data: map[string]any{"source": "web", "name": "Alicia", "city": nil}},
+		{ID: "r5", Data: map[string]any{"name": "Alice", "city": "Berlin"}},
+		{ID: "r6", Data: map[string]any{"source": "erp", "name": "Alicia", "city": "Munich"}},
+		nil,
+	}
+}
+
+func sourceTestWeighting() record.SourceWeighting {
+	return record.SourceWeighting{
+		SourcePath: "source",
+		Weights: map[string]float64{
+			"crm": 3,
+			"web": 0.5,
+		},
+	}
+}
+
+func TestSourceWeightedConfidence(t *testing.T) {
+	actual, err := record.SourceWeightedConfidence(sourceTestRecords(), "name", false, sourceTestWeighting())
+	require.NoError(t, err)
+	assertFloatPointer(t, pointer(53.0/81), actual)
+
+	weighting := sourceTestWeighting()
+	weighting.DefaultWeight = pointer(0.0)
+	actual, err = record.SourceWeightedConfidence(sourceTestRecords(), "name", false, weighting)
+	require.NoError(t, err)
+	assertFloatPointer(t, pointer(37.0/49), actual)
+
+	actual, err = record.SourceWeightedConfidence([]*api.Record{nil}, "name", false, sourceTestWeighting())
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	weighting = sourceTestWeighting()
+	weighting.Weights["erp"] = -1
+	_, err = record.SourceWeightedConfidence(sourceTestRecords(), "name", false, weighting)
+	assert.Error(t, err)
+
+	weighting = sourceTestWeighting()
+	weighting.DefaultWeight = pointer(-1.0)
+	_, err = record.SourceWeightedConfidence(sourceTestRecords(), "name", false, weighting)
+	assert.Error(t, err)
+}
+
+func TestSourceWeightedFrequencyDistribution(t *testing.T) {
+	cases := map[string]struct {
+		top      int
+		sortASC  bool
+		expected []*record.WeightedFrequencyDistributionEntry
+	}{
+		"all entries": {
+			top: -1,
+			expected: []*record.WeightedFrequencyDistributionEntry{
+				{Value: "Alice", Frequency: 3, Weight: 7, Percentage: 7.0 / 9},
+				{Value: "Alicia", Frequency: 3, Weight: 2, Percentage: 2.0 / 9},
+			},
+		},
+		"ascending": {
+			top:     -1,
+			sortASC: true,
+			expected: []*record.WeightedFrequencyDistributionEntry{
+				{Value: "Alicia", Frequency: 3, Weight: 2, Percentage: 2.0 / 9},
+				{Value: "Alice", Frequency: 3, Weight: 7, Percentage: 7.0 / 9},
+			},
+		},
+		"top 1": {
+			top: 1,
+			expected: []*record.WeightedFrequencyDistributionEntry{
+				{Value: "Alice", Frequency: 3, Weight: 7, Percentage: 7.0 / 9},
+			},
+		},
+		"top 0": {
+			top:      0,
+			expected: []*record.WeightedFrequencyDistributionEntry{},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.SourceWeightedFrequencyDistribution(sourceTestRecords(), "name", false, c.top, c.sortASC, sourceTestWeighting())
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSourceWeightedMode(t *testing.T) {
+	actual, err := record.SourceWeightedMode(sourceTestRecords(), "name", false, sourceTestWeighting())
+	require.NoError(t, err)
+	assert.Equal(t, &record.WeightedModeResult{
+		Values: []any{"Alice"},
+		Weight: 7,
+		Tie:    false,
+	}, actual)
+
+	weighting := sourceTestWeighting()
+	weighting.Weights = nil
+	actual, err = record.SourceWeightedMode(sourceTestRecords(), "name", false, weighting)
+	require.NoError(t, err)
+	assert.Equal(t, &record.WeightedModeResult{
+		Values: []any{"Alice", "Alicia"},
+		Weight: 3,
+		Tie:    true,
+	}, actual)
+
+	actual, err = record.SourceWeightedMode([]*api.Record{}, "name", false, sourceTestWeighting())
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+}
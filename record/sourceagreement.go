@@ -0,0 +1,133 @@
+package record
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// SourceAgreementEntry describes how often the records of a single source
+// agree with the majority values of all records.
+type SourceAgreementEntry struct {
+	// Source is the value of the source path or null for records without a
+	// source.
+	Source *string `json:"source"`
+	// Records is the number of records from that source.
+	Records int `json:"records"`
+	// Paths contains the agreement for each of the requested paths in the same
+	// order.
+	Paths []*SourcePathAgreement `json:"paths"`
+}
+
+// SourcePathAgreement describes how often the values of a single source agree
+// with the majority value for a path.
+type SourcePathAgreement struct {
+	Path string `json:"path"`
+	// Majority is the most common value of all records or null if there is no
+	// single most common value.
+	Majority any `json:"majority"`
+	// Values is the number of non-null values from the source.
+	Values int `json:"values"`
+	// Agreeing is the number of non-null values from the source that are equal
+	// to the majority value.
+	Agreeing int `json:"agreeing"`
+	// Agreement is the share of agreeing values, ranging from 0 to 1. It is
+	// null if the source has no values or if there is no majority value.
+	Agreement *float64 `json:"agreement"`
+}
+
+// SourceAgreement returns for each source how often its values agree with the
+// majority value of all records for each of the provided paths.
+//
+// The source of a record is the case-sensitive value of the source path.
+// Values are compared the same way as in Mode. If multiple values share the
+// highest frequency, then there is no majority value and the agreement for
+// that path is null.
+//
+// The sources are returned in the order of their first occurrence.
+func SourceAgreement(records []*api.Record, sourcePath string, paths []string, caseSensitive bool) ([]*SourceAgreementEntry, error) {
+	majorities, err := pathMajorities(records, paths, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*SourceAgreementEntry, 0)
+	idx := make(map[string]*SourceAgreementEntry)
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		entry, err := sourceAgreementEntry(record, sourcePath, paths, majorities, idx)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Records == 0 {
+			result = append(result, entry)
+		}
+		entry.Records++
+		if err := countSourceAgreement(entry, record, paths, majorities, caseSensitive); err != nil {
+			return nil, err
+		}
+	}
+
+	calculateSourceAgreement(result)
+	return result, nil
+}
+
+// countSourceAgreement adds the values of the record and how many of them
+// agree with the majority values to the entry.
+func countSourceAgreement(entry *SourceAgreementEntry, record *api.Record, paths []string, majorities []*weightedValue, caseSensitive bool) error {
+	for i, path := range paths {
+		p := entry.Paths[i]
+		err := VisitString([]*api.Record{record}, path, caseSensitive, func(val *string, _ *api.Record) error {
+			if val == nil {
+				return nil
+			}
+			p.Values++
+			if majorities[i] != nil && majorities[i].key == *val {
+				p.Agreeing++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func calculateSourceAgreement(entries []*SourceAgreementEntry) {
+	for _, entry := range entries {
+		for _, p := range entry.Paths {
+			if p.Majority != nil && p.Values > 0 {
+				p.Agreement = pointer(float64(p.Agreeing) / float64(p.Values))
+			}
+		}
+	}
+}
+
+func sourceAgreementEntry(record *api.Record, sourcePath string, paths []string, majorities []*weightedValue, idx map[string]*SourceAgreementEntry) (*SourceAgreementEntry, error) {
+	source, err := ExtractString(record, sourcePath, true)
+	if err != nil {
+		return nil, err
+	}
+	key := ":n:"
+	if source != nil {
+		key = ":s:" + *source
+	}
+	if entry, ok := idx[key]; ok {
+		return entry, nil
+	}
+	entry := &SourceAgreementEntry{
+		Source: source,
+		Paths:  make([]*SourcePathAgreement, len(paths)),
+	}
+	for i, path := range paths {
+		entry.Paths[i] = &SourcePathAgreement{
+			Path: path,
+		}
+		if majorities[i] != nil {
+			entry.Paths[i].Majority = majorities[i].value
+		}
+	}
+	idx[key] = entry
+	return entry, nil
+}
@@ -0,0 +1,66 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestSourceAgreement(t *testing.T) {
+	actual, err := record.SourceAgreement(sourceTestRecords(), "source", []string{"name", "city"}, false)
+	require.NoError(t, err)
+
+	expected := []*record.SourceAgreementEntry{
+		{
+			Source:  pointer("crm"),
+			Records: 2,
+			Paths: []*record.SourcePathAgreement{
+				{Path: "name", Values: 2},
+				{Path: "city", Majority: "Berlin", Values: 2, Agreeing: 2, Agreement: pointer(1.0)},
+			},
+		},
+		{
+			Source:  pointer("web"),
+			Records: 2,
+			Paths: []*record.SourcePathAgreement{
+				{Path: "name", Values: 2},
+				{Path: "city", Majority: "Berlin", Values: 1, Agreeing: 0, Agreement: pointer(0.0)},
+			},
+		},
+		{
+			Source:  nil,
+			Records: 1,
+			Paths: []*record.SourcePathAgreement{
+				{Path: "name", Values: 1},
+				{Path: "city", Majority: "Berlin", Values: 1, Agreeing: 1, Agreement: pointer(1.0)},
+			},
+		},
+		{
+			Source:  pointer("erp"),
+			Records: 1,
+			Paths: []*record.SourcePathAgreement{
+				{Path: "name", Values: 1},
+				{Path: "city", Majority: "Berlin", Values: 1, Agreeing: 0, Agreement: pointer(0.0)},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestSourceAgreementCaseSensitive(t *testing.T) {
+	actual, err := record.SourceAgreement(sourceTestRecords(), "source", []string{"name"}, true)
+	require.NoError(t, err)
+	require.Len(t, actual, 4)
+	assert.Equal(t, "Alicia", actual[0].Paths[0].Majority)
+	assert.Equal(t, pointer(0.0), actual[0].Paths[0].Agreement)
+	assert.Equal(t, pointer(1.0), actual[1].Paths[0].Agreement)
+}
+
+func TestSourceAgreementEmpty(t *testing.T) {
+	actual, err := record.SourceAgreement([]*api.Record{}, "source", []string{"name"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []*record.SourceAgreementEntry{}, actual)
+}
@@ -1,40 +1,33 @@
 package record
 
 import (
-	"math"
-
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
-// StandardDeviation calculates the standard deviation for the provided numeric
-// path.
+// StandardDeviation calculates the population standard deviation for the
+// provided numeric path.
 //
 // Using standardDeviation on non-numeric paths will raise an error.
 // Null values are ignored in the calculation.
 // Returns null if all values are null.
 func StandardDeviation(records []*api.Record, path string) (*float64, error) {
-	if len(records) == 0 {
-		return nil, nil
-	}
-	avg, err := Average(records, path)
+	m, err := collectMoments(records, path)
 	if err != nil {
 		return nil, err
 	}
-	difSquareSum := 0.0
-	counted := 0.0
-	err = VisitNumber(records, path, func(number *float64, _ *api.Record) error {
-		if number != nil {
-			dif := *number - *avg
-			difSquareSum += dif * dif
-			counted++
-		}
-		return nil
-	})
+	return m.standardDeviation(), nil
+}
+
+// SampleStandardDeviation calculates the sample standard deviation (using n-1)
+// for the provided numeric path.
+//
+// Using sampleStandardDeviation on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if there are less than two non-null values.
+func SampleStandardDeviation(records []*api.Record, path string) (*float64, error) {
+	m, err := collectMoments(records, path)
 	if err != nil {
 		return nil, err
 	}
-	if counted == 0 {
-		return nil, nil
-	}
-	return pointer(math.Sqrt(difSquareSum / counted)), nil
+	return m.sampleStandardDeviation(), nil
 }
@@ -1,20 +1,25 @@
 package record
 
 import (
+	"math/big"
+
 	api "github.com/tilotech/tilores-plugin-api"
 )
 
 // Sum returns the sum of the provided numeric path.
 //
+// The values are added using compensated summation to avoid rounding drift
+// when summing up many values.
+//
 // Using sum on non-numeric paths will raise an error.
 // Null values are ignored in the calculation.
 // Returns null if all values are null.
 func Sum(records []*api.Record, path string) (*float64, error) {
-	sum := 0.0
+	sum := compensatedSum{}
 	counted := 0.0
 	err := VisitNumber(records, path, func(number *float64, _ *api.Record) error {
 		if number != nil {
-			sum += *number
+			sum.add(*number)
 			counted++
 		}
 		return nil
@@ -25,5 +30,38 @@ func Sum(records []*api.Record, path string) (*float64, error) {
 	if counted == 0 {
 		return nil, nil
 	}
-	return pointer(sum), nil
+	return pointer(sum.value()), nil
+}
+
+// SumDecimal returns the exact sum of the provided numeric path as a decimal
+// string.
+//
+// The values are parsed into arbitrary-precision decimals, so no precision is
+// lost. The result has as many decimal places as the most precise value.
+//
+// Using sumDecimal on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func SumDecimal(records []*api.Record, path string) (*string, error) {
+	sum, scale, counted, err := sumDecimal(records, path)
+	if err != nil {
+		return nil, err
+	}
+	if counted == 0 {
+		return nil, nil
+	}
+	return pointer(formatDecimal(sum, scale)), nil
+}
+
+func sumDecimal(records []*api.Record, path string) (*big.Rat, int, int, error) {
+	sum := new(big.Rat)
+	maxScale := 0
+	counted := 0
+	err := visitDecimal(records, path, func(val *big.Rat, scale int) error {
+		sum.Add(sum, val)
+		maxScale = max(maxScale, scale)
+		counted++
+		return nil
+	})
+	return sum, maxScale, counted, err
 }
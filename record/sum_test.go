@@ -1,6 +1,8 @@
 package record_test
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -80,6 +82,22 @@ func TestSum(t *testing.T) {
 			},
 			expectError: true,
 		},
+		"many small values do not drift": {
+			records:  numRecords(0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1),
+			expected: pointer(1.0),
+		},
+		"overflowing values": {
+			records:  numRecords(1e308, 1e308),
+			expected: pointer(math.Inf(1)),
+		},
+		"infinite values": {
+			records:  numRecords("Inf", 1.0),
+			expected: pointer(math.Inf(1)),
+		},
+		"cancelling large values keep small values": {
+			records:  numRecords(1e100, 1.0, -1e100),
+			expected: pointer(1.0),
+		},
 	}
 
 	for name, c := range cases {
@@ -96,3 +114,87 @@ func TestSum(t *testing.T) {
 		})
 	}
 }
+
+func TestSumDecimal(t *testing.T) {
+	cases := map[string]struct {
+		records     []*api.Record
+		expected    *string
+		expectError bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+		},
+		"list with all nil values": {
+			records: []*api.Record{nil, {ID: "r1", Data: map[string]any{}}},
+		},
+		"exact sum of floats": {
+			records:  numRecords(0.1, 0.2),
+			expected: pointer("0.3"),
+		},
+		"keeps highest scale": {
+			records:  numRecords("10.50", "0.25", 3.0, nil),
+			expected: pointer("13.75"),
+		},
+		"trailing zeros": {
+			records:  numRecords("1.10", "2.20"),
+			expected: pointer("3.30"),
+		},
+		"exponents": {
+			records:  numRecords("1e-3", "2E2", "-1.5e1", "+.5", "3."),
+			expected: pointer("188.501"),
+		},
+		"large values": {
+			records:  numRecords("12345678901234567890.12", "0.01"),
+			expected: pointer("12345678901234567890.13"),
+		},
+		"non numbers values causes an error": {
+			records:     numRecords("10.0", "not number"),
+			expectError: true,
+		},
+		"fractions cause an error": {
+			records:     numRecords("1/3"),
+			expectError: true,
+		},
+		"hexadecimal values cause an error": {
+			records:     numRecords("0x10"),
+			expectError: true,
+		},
+		"binary values cause an error": {
+			records:     numRecords("0b10"),
+			expectError: true,
+		},
+		"infinite values cause an error": {
+			records:     numRecords("Inf"),
+			expectError: true,
+		},
+		"non numeric types cause an error": {
+			records:     numRecords(true),
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.SumDecimal(c.records, "num")
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func numRecords(values ...any) []*api.Record {
+	records := make([]*api.Record, 0, len(values))
+	for i, v := range values {
+		records = append(records, &api.Record{
+			ID: fmt.Sprintf("r%v", i+1),
+			Data: map[string]any{
+				"num": v,
+			},
+		})
+	}
+	return records
+}
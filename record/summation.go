@@ -0,0 +1,35 @@
+package record
+
+import (
+	"math"
+)
+
+// compensatedSum is a float64 sum using Kahan–Neumaier summation, which keeps
+// track of the rounding error lost in each addition to avoid drift when adding
+// many values.
+type compensatedSum struct {
+	sum          float64
+	compensation float64
+}
+
+func (s *compensatedSum) add(x float64) {
+	t := s.sum + x
+	if math.IsInf(t, 0) || math.IsNaN(t) {
+		// the compensation would turn infinities into NaN
+		s.sum = t
+		return
+	}
+	if math.Abs(s.sum) >= math.Abs(x) {
+		s.compensation += (s.sum - t) + x
+	} else {
+		s.compensation += (x - t) + s.sum
+	}
+	s.sum = t
+}
+
+func (s *compensatedSum) value() float64 {
+	if math.IsInf(s.sum, 0) || math.IsNaN(s.sum) {
+		return s.sum
+	}
+	return s.sum + s.compensation
+}
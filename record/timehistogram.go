@@ -0,0 +1,159 @@
+package record
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
+)
+
+// TimeInterval defines the size of the buckets of a time histogram.
+type TimeInterval string
+
+// Supported time intervals.
+//
+// All intervals are calendar-aware in the provided location, e.g. a day
+// always starts at midnight, even on days with daylight saving time changes.
+// TimeIntervalWeek uses ISO weeks, which start on Monday.
+const (
+	TimeIntervalHour    TimeInterval = "hour"
+	TimeIntervalDay     TimeInterval = "day"
+	TimeIntervalWeek    TimeInterval = "week"
+	TimeIntervalMonth   TimeInterval = "month"
+	TimeIntervalQuarter TimeInterval = "quarter"
+	TimeIntervalYear    TimeInterval = "year"
+)
+
+// TimeHistogramEntry represents a single bucket of a time histogram.
+//
+// A bucket contains all times from Start (inclusive) to End (exclusive).
+type TimeHistogramEntry struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Frequency  int       `json:"frequency"`
+	Percentage float64   `json:"percentage"`
+}
+
+// TimeHistogram returns how many values of the provided time path fall into
+// each bucket of the given interval.
+//
+// Buckets are calculated in the provided location, which defaults to UTC. The
+// results are ordered by their start time. By default only buckets with at
+// least one value are returned. Using the 'fillEmpty' option, all buckets
+// between the first and the last value are returned.
+//
+// Using timeHistogram on non-time paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns an empty list if all values are null.
+func TimeHistogram(records []*api.Record, path string, interval TimeInterval, location *time.Location, fillEmpty bool) ([]*TimeHistogramEntry, error) {
+	if !isTimeInterval(interval) {
+		return nil, fmt.Errorf("unsupported time interval %q", interval)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	buckets := map[int64]*TimeHistogramEntry{}
+	counted := 0
+	err := VisitTime(records, path, func(t *time.Time, _ *api.Record) error {
+		if t == nil {
+			return nil
+		}
+		start := timeBucketStart(*t, interval, location)
+		bucket, ok := buckets[start.UnixNano()]
+		if !ok {
+			bucket = &TimeHistogramEntry{
+				Start: start,
+				End:   timeBucketNext(start, interval, location),
+			}
+			buckets[start.UnixNano()] = bucket
+		}
+		bucket.Frequency++
+		counted++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TimeHistogramEntry, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket.Percentage = float64(bucket.Frequency) / float64(counted)
+		result = append(result, bucket)
+	}
+	slices.SortFunc(result, func(a, b *TimeHistogramEntry) int {
+		return cmp.Compare(a.Start.UnixNano(), b.Start.UnixNano())
+	})
+	if fillEmpty && len(result) > 1 {
+		return fillTimeHistogram(result, interval, location)
+	}
+	return result, nil
+}
+
+func fillTimeHistogram(buckets []*TimeHistogramEntry, interval TimeInterval, location *time.Location) ([]*TimeHistogramEntry, error) {
+	result := make([]*TimeHistogramEntry, 0, len(buckets))
+	last := buckets[len(buckets)-1].Start
+	next := 0
+	for start := buckets[0].Start; !start.After(last); start = timeBucketNext(start, interval, location) {
+		if len(result) >= maxHistogramBins {
+			return nil, fmt.Errorf("time interval %v results in more than %v buckets", interval, maxHistogramBins)
+		}
+		if buckets[next].Start.Equal(start) {
+			result = append(result, buckets[next])
+			next++
+			continue
+		}
+		result = append(result, &TimeHistogramEntry{
+			Start: start,
+			End:   timeBucketNext(start, interval, location),
+		})
+	}
+	return result, nil
+}
+
+func isTimeInterval(interval TimeInterval) bool {
+	switch interval {
+	case TimeIntervalHour, TimeIntervalDay, TimeIntervalWeek, TimeIntervalMonth, TimeIntervalQuarter, TimeIntervalYear:
+		return true
+	}
+	return false
+}
+
+func timeBucketStart(t time.Time, interval TimeInterval, location *time.Location) time.Time {
+	t = t.In(location)
+	year, month, day := t.Date()
+	switch interval {
+	case TimeIntervalHour:
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case TimeIntervalDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, location)
+	case TimeIntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, location)
+	case TimeIntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, location)
+	case TimeIntervalQuarter:
+		return time.Date(year, ((month-1)/3)*3+1, 1, 0, 0, 0, 0, location)
+	default:
+		return time.Date(year, 1, 1, 0, 0, 0, 0, location)
+	}
+}
+
+func timeBucketNext(start time.Time, interval TimeInterval, location *time.Location) time.Time {
+	switch interval {
+	case TimeIntervalHour:
+		return start.Add(time.Hour)
+	case TimeIntervalDay:
+		return timeBucketStart(start.AddDate(0, 0, 1), interval, location)
+	case TimeIntervalWeek:
+		return timeBucketStart(start.AddDate(0, 0, 7), interval, location)
+	case TimeIntervalMonth:
+		return timeBucketStart(start.AddDate(0, 1, 0), interval, location)
+	case TimeIntervalQuarter:
+		return timeBucketStart(start.AddDate(0, 3, 0), interval, location)
+	default:
+		return timeBucketStart(start.AddDate(1, 0, 0), interval, location)
+	}
+}
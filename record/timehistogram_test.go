@@ -0,0 +1,233 @@
+package record_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestTimeHistogram(t *testing.T) {
+	testRecords := []*api.Record{
+		{ID: "r1", Data: map[string]any{"time": "2024-01-31T23:30:00Z"}},
+		{ID: "r2", Data: map[string]any{"time": "2024-02-01T00:15:00+01:00"}},
+		{ID: "r3", Data: map[string]any{"time": "2024-04-01T12:00:00Z"}},
+		{ID: "r4", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+		{ID: "r5", Data: map[string]any{}},
+		nil,
+	}
+	cet := time.FixedZone("CET", 60*60)
+
+	cases := map[string]struct {
+		records     []*api.Record
+		interval    record.TimeInterval
+		location    *time.Location
+		fillEmpty   bool
+		expected    []*record.TimeHistogramEntry
+		expectError bool
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			interval: record.TimeIntervalDay,
+			expected: []*record.TimeHistogramEntry{},
+		},
+		"month in UTC": {
+			records:  testRecords,
+			interval: record.TimeIntervalMonth,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  3,
+					Percentage: 0.75,
+				},
+				{
+					Start:      time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  1,
+					Percentage: 0.25,
+				},
+			},
+		},
+		"month in other location": {
+			records:  testRecords,
+			interval: record.TimeIntervalMonth,
+			location: cet,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, cet),
+					End:        time.Date(2024, 2, 1, 0, 0, 0, 0, cet),
+					Frequency:  1,
+					Percentage: 0.25,
+				},
+				{
+					Start:      time.Date(2024, 2, 1, 0, 0, 0, 0, cet),
+					End:        time.Date(2024, 3, 1, 0, 0, 0, 0, cet),
+					Frequency:  2,
+					Percentage: 0.5,
+				},
+				{
+					Start:      time.Date(2024, 4, 1, 0, 0, 0, 0, cet),
+					End:        time.Date(2024, 5, 1, 0, 0, 0, 0, cet),
+					Frequency:  1,
+					Percentage: 0.25,
+				},
+			},
+		},
+		"month with empty buckets": {
+			records:   testRecords,
+			interval:  record.TimeIntervalMonth,
+			fillEmpty: true,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  3,
+					Percentage: 0.75,
+				},
+				{
+					Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+					End:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Start:      time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  1,
+					Percentage: 0.25,
+				},
+			},
+		},
+		"quarter": {
+			records:  testRecords,
+			interval: record.TimeIntervalQuarter,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  3,
+					Percentage: 0.75,
+				},
+				{
+					Start:      time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  1,
+					Percentage: 0.25,
+				},
+			},
+		},
+		"year": {
+			records:  testRecords,
+			interval: record.TimeIntervalYear,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  4,
+					Percentage: 1,
+				},
+			},
+		},
+		"iso week": {
+			records:  testRecords[:2],
+			interval: record.TimeIntervalWeek,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+					Frequency:  2,
+					Percentage: 1,
+				},
+			},
+		},
+		"day": {
+			records:  testRecords[:2],
+			interval: record.TimeIntervalDay,
+			location: cet,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 2, 1, 0, 0, 0, 0, cet),
+					End:        time.Date(2024, 2, 2, 0, 0, 0, 0, cet),
+					Frequency:  2,
+					Percentage: 1,
+				},
+			},
+		},
+		"hour with empty buckets": {
+			records:   testRecords[:2],
+			interval:  record.TimeIntervalHour,
+			fillEmpty: true,
+			expected: []*record.TimeHistogramEntry{
+				{
+					Start:      time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC),
+					End:        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+					Frequency:  2,
+					Percentage: 1,
+				},
+			},
+		},
+		"invalid interval": {
+			records:     testRecords,
+			interval:    "decade",
+			expectError: true,
+		},
+		"too many empty buckets": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"time": "2000-01-01T00:00:00Z"}},
+				{ID: "r2", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+			},
+			interval:    record.TimeIntervalHour,
+			fillEmpty:   true,
+			expectError: true,
+		},
+		"non time values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"time": "yesterday"}},
+			},
+			interval:    record.TimeIntervalDay,
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.TimeHistogram(c.records, "time", c.interval, c.location, c.fillEmpty)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, len(c.expected))
+			for i := range c.expected {
+				assert.True(t, c.expected[i].Start.Equal(actual[i].Start), "start %v: expected %v, got %v", i, c.expected[i].Start, actual[i].Start)
+				assert.True(t, c.expected[i].End.Equal(actual[i].End), "end %v: expected %v, got %v", i, c.expected[i].End, actual[i].End)
+				assert.Equal(t, c.expected[i].Frequency, actual[i].Frequency)
+				assert.InDelta(t, c.expected[i].Percentage, actual[i].Percentage, 1e-9)
+			}
+		})
+	}
+}
+
+func TestTimeHistogramDaylightSavingTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	records := []*api.Record{
+		{ID: "r1", Data: map[string]any{"time": "2024-03-30T23:30:00Z"}},
+		{ID: "r2", Data: map[string]any{"time": "2024-03-31T21:30:00Z"}},
+	}
+
+	actual, err := record.TimeHistogram(records, "time", record.TimeIntervalDay, berlin, false)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.True(t, time.Date(2024, 3, 31, 0, 0, 0, 0, berlin).Equal(actual[0].Start))
+	assert.True(t, time.Date(2024, 4, 1, 0, 0, 0, 0, berlin).Equal(actual[0].End))
+	assert.Equal(t, 23*time.Hour, actual[0].End.Sub(actual[0].Start))
+}
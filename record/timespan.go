@@ -0,0 +1,57 @@
+package record
+
+import (
+	"time"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// TimeSpan returns the duration between the earliest and the latest value of
+// the provided time path.
+//
+// Using timeSpan on non-time paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func TimeSpan(records []*api.Record, path string) (*time.Duration, error) {
+	earliest, latest, _, err := timeBounds(records, path)
+	if err != nil || earliest == nil {
+		return nil, err
+	}
+	return pointer(latest.Sub(*earliest)), nil
+}
+
+// AverageInterval returns the average duration between two consecutive values
+// of the provided time path.
+//
+// Using averageInterval on non-time paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if there are less than two non-null values.
+func AverageInterval(records []*api.Record, path string) (*time.Duration, error) {
+	earliest, latest, counted, err := timeBounds(records, path)
+	if err != nil || counted < 2 {
+		return nil, err
+	}
+	return pointer(latest.Sub(*earliest) / time.Duration(counted-1)), nil
+}
+
+func timeBounds(records []*api.Record, path string) (*time.Time, *time.Time, int, error) {
+	var earliest, latest *time.Time
+	counted := 0
+	err := VisitTime(records, path, func(t *time.Time, _ *api.Record) error {
+		if t == nil {
+			return nil
+		}
+		counted++
+		if earliest == nil || t.Before(*earliest) {
+			earliest = t
+		}
+		if latest == nil || t.After(*latest) {
+			latest = t
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return earliest, latest, counted, nil
+}
@@ -0,0 +1,67 @@
+package record_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestTimeSpan(t *testing.T) {
+	cases := map[string]struct {
+		records                 []*api.Record
+		expectedTimeSpan        *time.Duration
+		expectedAverageInterval *time.Duration
+		expectError             bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+		},
+		"list with all nil values": {
+			records: []*api.Record{nil, {ID: "r1", Data: map[string]any{}}},
+		},
+		"single value": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+			},
+			expectedTimeSpan: pointer(time.Duration(0)),
+		},
+		"different values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"time": "2024-01-03T00:00:00Z"}},
+				{ID: "r2", Data: map[string]any{"time": "2024-01-01T00:00:00Z"}},
+				{ID: "r3", Data: map[string]any{"time": "2024-01-02T01:00:00+01:00"}},
+				{ID: "r4", Data: map[string]any{"time": "2024-01-07T00:00:00Z"}},
+				{ID: "r5", Data: map[string]any{}},
+				nil,
+			},
+			expectedTimeSpan:        pointer(6 * 24 * time.Hour),
+			expectedAverageInterval: pointer(2 * 24 * time.Hour),
+		},
+		"non time values": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"time": "yesterday"}},
+			},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			timeSpan, timeSpanErr := record.TimeSpan(c.records, "time")
+			averageInterval, averageIntervalErr := record.AverageInterval(c.records, "time")
+			if c.expectError {
+				assert.Error(t, timeSpanErr)
+				assert.Error(t, averageIntervalErr)
+				return
+			}
+			require.NoError(t, timeSpanErr)
+			require.NoError(t, averageIntervalErr)
+			assert.Equal(t, c.expectedTimeSpan, timeSpan)
+			assert.Equal(t, c.expectedAverageInterval, averageInterval)
+		})
+	}
+}
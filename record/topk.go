@@ -0,0 +1,124 @@
+package record
+
+import (
+	"container/heap"
+	"sort"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// TopK returns up to k records in the order defined by the provided
+// SortCriteria, skipping the first 'offset' records.
+//
+// The result is identical to Limit(Sort(records, criteria), k, offset), but
+// instead of sorting all records only the k+offset first records are kept
+// while iterating. Only the values of the first SortCriteria are extracted
+// for all records, values of further criteria are only extracted for records
+// that tie on all previous criteria. This makes it considerably faster for
+// large record lists and small values of k.
+//
+// Invalid values for further criteria only raise an error if they are needed
+// for a comparison.
+func TopK(records []*api.Record, criteria []*SortCriteria, k int, offset int) ([]*api.Record, error) {
+	l := len(records)
+	if k <= 0 || offset < 0 || offset >= l {
+		return []*api.Record{}, nil
+	}
+	if len(criteria) == 0 {
+		return Limit(records, k, offset), nil
+	}
+
+	data, err := topKCollectData(records, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	n := l
+	if k < l-offset {
+		n = k + offset
+	}
+	h := &topKHeap{
+		idx: make([]int, 0, n),
+		before: func(i, j int) bool {
+			if sortRecordLess(data, criteria, i, j) {
+				return true
+			}
+			return !sortRecordLess(data, criteria, j, i) && i < j
+		},
+	}
+	for i := range records {
+		if h.Len() < n {
+			heap.Push(h, i)
+			continue
+		}
+		if h.before(i, h.idx[0]) {
+			h.idx[0] = i
+			heap.Fix(h, 0)
+		}
+	}
+
+	selected := h.idx
+	sort.Slice(selected, func(i, j int) bool {
+		return h.before(selected[i], selected[j])
+	})
+
+	for _, values := range data {
+		if values.err != nil {
+			return nil, values.err
+		}
+	}
+
+	result := make([]*api.Record, 0, len(selected)-offset)
+	for _, i := range selected[offset:] {
+		result = append(result, records[i])
+	}
+	return result, nil
+}
+
+// topKCollectData extracts the values of the first criteria for all records
+// and prepares the lazy extraction for all further criteria.
+func topKCollectData(records []*api.Record, criteria []*SortCriteria) ([]sortValues, error) {
+	first, err := sortCollectCriteria(records, criteria[0])
+	if err != nil {
+		return nil, err
+	}
+	data := make([]sortValues, 0, len(criteria))
+	data = append(data, first)
+	for _, c := range criteria[1:] {
+		values, err := sortLazyCriteria(records, c)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, values)
+	}
+	return data, nil
+}
+
+// topKHeap is a max heap of record positions, where the record that would be
+// sorted last is at the top.
+type topKHeap struct {
+	idx    []int
+	before func(i, j int) bool
+}
+
+func (h *topKHeap) Len() int {
+	return len(h.idx)
+}
+
+func (h *topKHeap) Less(i, j int) bool {
+	return h.before(h.idx[j], h.idx[i])
+}
+
+func (h *topKHeap) Swap(i, j int) {
+	h.idx[i], h.idx[j] = h.idx[j], h.idx[i]
+}
+
+func (h *topKHeap) Push(x any) {
+	h.idx = append(h.idx, x.(int))
+}
+
+func (h *topKHeap) Pop() any {
+	last := h.idx[len(h.idx)-1]
+	h.idx = h.idx[:len(h.idx)-1]
+	return last
+}
@@ -0,0 +1,234 @@
+package record_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	insights "github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestTopK(t *testing.T) {
+	r1 := &api.Record{
+		ID: "r1",
+		Data: map[string]any{
+			"number": 10.0,
+			"group":  "b",
+		},
+	}
+	r2 := &api.Record{
+		ID: "r2",
+		Data: map[string]any{
+			"number": 5.0,
+			"group":  "a",
+		},
+	}
+	r3 := &api.Record{
+		ID: "r3",
+		Data: map[string]any{
+			"number": 5.0,
+			"group":  "b",
+		},
+	}
+	r4 := &api.Record{
+		ID: "r4",
+		Data: map[string]any{
+			"number": nil,
+			"group":  "a",
+		},
+	}
+	r5 := &api.Record{
+		ID: "r5",
+		Data: map[string]any{
+			"number": "not a number",
+		},
+	}
+	r6 := &api.Record{
+		ID: "r6",
+		Data: map[string]any{
+			"number": "not a number",
+			"group":  "a",
+		},
+	}
+	defaultRecords := []*api.Record{
+		r1, r2, r3, r4,
+	}
+	groupThenNumber := []*insights.SortCriteria{
+		{
+			Path: "group",
+			ASC:  true,
+		},
+		{
+			Path: "number",
+			Type: insights.SortTypeNumber,
+		},
+	}
+	numberASC := []*insights.SortCriteria{
+		{
+			Path: "number",
+			ASC:  true,
+		},
+	}
+
+	cases := map[string]struct {
+		records     []*api.Record
+		criteria    []*insights.SortCriteria
+		k           int
+		offset      int
+		expected    []*api.Record
+		expectError bool
+	}{
+		"empty list": {
+			records:  []*api.Record{},
+			criteria: numberASC,
+			k:        2,
+			expected: []*api.Record{},
+		},
+		"no criteria": {
+			records:  defaultRecords,
+			criteria: []*insights.SortCriteria{},
+			k:        2,
+			offset:   1,
+			expected: []*api.Record{r2, r3},
+		},
+		"zero k": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        0,
+			expected: []*api.Record{},
+		},
+		"negative offset": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        2,
+			offset:   -1,
+			expected: []*api.Record{},
+		},
+		"offset too large": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        2,
+			offset:   4,
+			expected: []*api.Record{},
+		},
+		"top 2 ASC with stable ties": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        2,
+			expected: []*api.Record{r2, r3},
+		},
+		"top 2 with offset": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        2,
+			offset:   1,
+			expected: []*api.Record{r3, r1},
+		},
+		"k larger than list": {
+			records:  defaultRecords,
+			criteria: numberASC,
+			k:        10,
+			expected: []*api.Record{r2, r3, r1, r4},
+		},
+		"top 1 DESC": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "number",
+					ASC:  false,
+				},
+			},
+			k:        1,
+			expected: []*api.Record{r4},
+		},
+		"multiple criteria": {
+			records: defaultRecords,
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "group",
+					ASC:  true,
+				},
+				{
+					Path: "number",
+					ASC:  false,
+				},
+			},
+			k:        3,
+			expected: []*api.Record{r4, r2, r1},
+		},
+		"error": {
+			records: []*api.Record{r1, r5},
+			criteria: []*insights.SortCriteria{
+				{
+					Path: "number",
+					Type: insights.SortTypeNumber,
+				},
+			},
+			k:           1,
+			expectError: true,
+		},
+		"invalid value for later criteria without tie": {
+			records:  []*api.Record{r5, r1},
+			criteria: groupThenNumber,
+			k:        2,
+			expected: []*api.Record{r1, r5},
+		},
+		"invalid value for later criteria with tie": {
+			records:     []*api.Record{r1, r2, r6},
+			criteria:    groupThenNumber,
+			k:           1,
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := insights.TopK(c.records, c.criteria, c.k, c.offset)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestTopKMatchesSortAndLimit(t *testing.T) {
+	records := make([]*api.Record, 0, 200)
+	for i := range 200 {
+		data := map[string]any{
+			"number": float64((i * 37) % 23),
+			"text":   fmt.Sprintf("item%d", (i*13)%7),
+		}
+		if i%11 == 0 {
+			data["number"] = nil
+		}
+		records = append(records, &api.Record{
+			ID:   fmt.Sprintf("r%d", i),
+			Data: data,
+		})
+	}
+	criteria := []*insights.SortCriteria{
+		{
+			Path: "text",
+			ASC:  false,
+		},
+		{
+			Path: "number",
+			ASC:  true,
+		},
+	}
+
+	sorted, err := insights.Sort(records, criteria)
+	require.NoError(t, err)
+	for _, k := range []int{1, 5, 50, 250} {
+		for _, offset := range []int{0, 3, 150} {
+			actual, err := insights.TopK(records, criteria, k, offset)
+			require.NoError(t, err)
+			assert.Equal(t, insights.Limit(sorted, k, offset), actual, "k=%v offset=%v", k, offset)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package record
+
+import "fmt"
+
+// ValidationError describes an invalid value of a FilterCondition or
+// SortCriteria.
+//
+// Field uses the same name as the JSON and YAML representation.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid value for field %v: %v", e.Field, e.Message)
+}
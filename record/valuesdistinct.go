@@ -2,6 +2,7 @@ package record
 
 import (
 	api "github.com/tilotech/tilores-plugin-api"
+	"golang.org/x/exp/slices"
 )
 
 // ValuesDistinct returns all unique non-null values of the current records.
@@ -25,3 +26,40 @@ func ValuesDistinct(records []*api.Record, path string, caseSensitive bool) ([]a
 	})
 	return result, err
 }
+
+// ValuesDistinctCollated is a variant of ValuesDistinct that returns the values
+// ordered according to the provided collation.
+//
+// The collation is a BCP 47 language tag as described for SortCriteria.
+// Values are compared using their string representation. Values that are
+// equal according to the collation keep the order of their first occurrence.
+func ValuesDistinctCollated(records []*api.Record, path string, caseSensitive bool, collation string) ([]any, error) {
+	compareString, err := collationCompare(collation)
+	if err != nil {
+		return nil, err
+	}
+	values, err := ValuesDistinct(records, path, caseSensitive)
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		value any
+		key   string
+	}
+	entries := make([]entry, 0, len(values))
+	for _, v := range values {
+		key, err := valueToString(v, caseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{value: v, key: *key})
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return compareString(a.key, b.key)
+	})
+	result := make([]any, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e.value)
+	}
+	return result, nil
+}
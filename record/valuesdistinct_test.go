@@ -161,3 +161,51 @@ func TestValuesDistinct(t *testing.T) {
 		})
 	}
 }
+
+func TestValuesDistinctCollated(t *testing.T) {
+	testRecords := []*api.Record{
+		{ID: "r1", Data: map[string]any{"name": "Öl"}},
+		{ID: "r2", Data: map[string]any{"name": "Zoë"}},
+		{ID: "r3", Data: map[string]any{"name": "Ångström"}},
+		{ID: "r4", Data: map[string]any{"name": "apfel"}},
+		{ID: "r5", Data: map[string]any{"name": "Apfel"}},
+		{ID: "r6", Data: map[string]any{"name": nil}},
+	}
+
+	cases := map[string]struct {
+		collation     string
+		caseSensitive bool
+		expected      []any
+		expectError   bool
+	}{
+		"without collation": {
+			collation: "",
+			expected:  []any{"apfel", "Zoë", "Ångström", "Öl"},
+		},
+		"default collation": {
+			collation: "und",
+			expected:  []any{"Ångström", "apfel", "Öl", "Zoë"},
+		},
+		"danish collation, case sensitive": {
+			collation:     "da",
+			caseSensitive: true,
+			expected:      []any{"apfel", "Apfel", "Zoë", "Öl", "Ångström"},
+		},
+		"invalid collation": {
+			collation:   "not a language",
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.ValuesDistinctCollated(testRecords, "name", c.caseSensitive, c.collation)
+			if c.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package record
+
+import (
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// Variance calculates the population variance for the provided numeric path.
+//
+// Using variance on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if all values are null.
+func Variance(records []*api.Record, path string) (*float64, error) {
+	m, err := collectMoments(records, path)
+	if err != nil {
+		return nil, err
+	}
+	return m.variance(), nil
+}
+
+// SampleVariance calculates the sample variance (using n-1) for the provided
+// numeric path.
+//
+// Using sampleVariance on non-numeric paths will raise an error.
+// Null values are ignored in the calculation.
+// Returns null if there are less than two non-null values.
+func SampleVariance(records []*api.Record, path string) (*float64, error) {
+	m, err := collectMoments(records, path)
+	if err != nil {
+		return nil, err
+	}
+	return m.sampleVariance(), nil
+}
@@ -0,0 +1,118 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestVariance(t *testing.T) {
+	cases := map[string]struct {
+		records          []*api.Record
+		expected         *float64
+		expectedSample   *float64
+		expectedSampleSD *float64
+		expectError      bool
+	}{
+		"empty list": {
+			records: []*api.Record{},
+		},
+		"list with all nil values": {
+			records: []*api.Record{
+				nil,
+				nil,
+			},
+		},
+		"list with single value": {
+			records: []*api.Record{
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": 3.0,
+					},
+				},
+			},
+			expected: pointer(0.0),
+		},
+		"list with different values": {
+			records: []*api.Record{
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": "5",
+					},
+				},
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": "10.0",
+					},
+				},
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": "0",
+					},
+				},
+				{
+					ID:   "someid",
+					Data: map[string]any{},
+				},
+				nil,
+			},
+			expected:         pointer(50.0 / 3),
+			expectedSample:   pointer(25.0),
+			expectedSampleSD: pointer(5.0),
+		},
+		"list with non numbers values causes an error": {
+			records: []*api.Record{
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": "10.0",
+					},
+				},
+				{
+					ID: "someid",
+					Data: map[string]any{
+						"num": "not number",
+					},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := record.Variance(c.records, "num")
+			sample, sampleErr := record.SampleVariance(c.records, "num")
+			sampleSD, sampleSDErr := record.SampleStandardDeviation(c.records, "num")
+			if c.expectError {
+				assert.Error(t, err)
+				assert.Error(t, sampleErr)
+				assert.Error(t, sampleSDErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, sampleErr)
+			require.NoError(t, sampleSDErr)
+			assertFloatPointer(t, c.expected, actual)
+			assertFloatPointer(t, c.expectedSample, sample)
+			assertFloatPointer(t, c.expectedSampleSD, sampleSD)
+		})
+	}
+}
+
+func assertFloatPointer(t *testing.T, expected *float64, actual *float64) {
+	t.Helper()
+	if expected == nil {
+		assert.Nil(t, actual)
+		return
+	}
+	require.NotNil(t, actual)
+	assert.InDelta(t, *expected, *actual, 1e-9)
+}
@@ -0,0 +1,106 @@
+package record
+
+import (
+	"fmt"
+
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+// WeightedSum returns the sum of the provided numeric value path, where each
+// value is multiplied by the value of the numeric weight path.
+//
+// Value and weight are taken from the same record. If both paths contain a
+// wildcard at the same position, e.g. items.*.price and items.*.qty, then the
+// value and the weight are taken from the same array element.
+//
+// Pairs where either the value or the weight is null are ignored in the
+// calculation. Negative weights or paths that result in a different amount of
+// values will raise an error.
+// Returns null if all pairs are ignored.
+func WeightedSum(records []*api.Record, valuePath string, weightPath string) (*float64, error) {
+	sum := 0.0
+	counted := 0
+	err := visitWeighted(records, valuePath, weightPath, func(value, weight float64) {
+		sum += value * weight
+		counted++
+	})
+	if err != nil {
+		return nil, err
+	}
+	if counted == 0 {
+		return nil, nil
+	}
+	return pointer(sum), nil
+}
+
+// WeightedAverage returns the weighted average value of the provided numeric
+// value path, using the numeric weight path as weights.
+//
+// Value and weight are paired the same way as in WeightedSum and the same
+// rules for null and negative weights apply.
+// Returns null if all pairs are ignored or if the sum of all weights is zero.
+func WeightedAverage(records []*api.Record, valuePath string, weightPath string) (*float64, error) {
+	sum := 0.0
+	weightSum := 0.0
+	err := visitWeighted(records, valuePath, weightPath, func(value, weight float64) {
+		sum += value * weight
+		weightSum += weight
+	})
+	if err != nil {
+		return nil, err
+	}
+	if weightSum == 0 {
+		return nil, nil
+	}
+	return pointer(sum / weightSum), nil
+}
+
+func visitWeighted(records []*api.Record, valuePath string, weightPath string, visitor func(value, weight float64)) error {
+	return visitNumberPairs(records, valuePath, weightPath, func(value, weight float64) error {
+		if weight < 0 {
+			return fmt.Errorf("invalid negative weight %v for path %v", weight, weightPath)
+		}
+		visitor(value, weight)
+		return nil
+	})
+}
+
+// visitNumberPairs calls the visitor for each pair of non-null numbers of the
+// two provided paths.
+//
+// Both numbers are taken from the same record. If both paths contain a
+// wildcard at the same position, then both numbers are taken from the same
+// array element.
+func visitNumberPairs(records []*api.Record, pathA string, pathB string, visitor func(a, b float64) error) error {
+	for _, record := range records {
+		valuesA, err := collectNumbers(record, pathA)
+		if err != nil {
+			return err
+		}
+		valuesB, err := collectNumbers(record, pathB)
+		if err != nil {
+			return err
+		}
+		if len(valuesA) != len(valuesB) {
+			return fmt.Errorf("path %v and path %v result in a different amount of values", pathA, pathB)
+		}
+		for i := range valuesA {
+			if valuesA[i] == nil || valuesB[i] == nil {
+				continue
+			}
+			if err := visitor(*valuesA[i], *valuesB[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func collectNumbers(record *api.Record, path string) ([]*float64, error) {
+	numbers := []*float64{}
+	err := VisitNumber([]*api.Record{record}, path, func(number *float64, _ *api.Record) error {
+		numbers = append(numbers, number)
+		return nil
+	})
+	return numbers, err
+}
@@ -0,0 +1,121 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilotech/tilores-insights/record"
+	api "github.com/tilotech/tilores-plugin-api"
+)
+
+func TestWeighted(t *testing.T) {
+	cases := map[string]struct {
+		records         []*api.Record
+		valuePath       string
+		weightPath      string
+		expectedSum     *float64
+		expectedAverage *float64
+		expectError     bool
+	}{
+		"empty list": {
+			records:    []*api.Record{},
+			valuePath:  "price",
+			weightPath: "qty",
+		},
+		"list with all nil values": {
+			records:    []*api.Record{nil, nil},
+			valuePath:  "price",
+			weightPath: "qty",
+		},
+		"simple paths": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": 10.0, "qty": 1.0}},
+				{ID: "r2", Data: map[string]any{"price": "20", "qty": 3.0}},
+				{ID: "r3", Data: map[string]any{"price": 30.0}},
+				{ID: "r4", Data: map[string]any{"qty": 5.0}},
+				nil,
+			},
+			valuePath:       "price",
+			weightPath:      "qty",
+			expectedSum:     pointer(70.0),
+			expectedAverage: pointer(17.5),
+		},
+		"wildcard paths": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"items": []any{
+					map[string]any{"price": 2.0, "qty": 3.0},
+					map[string]any{"price": 10.0, "qty": 1.0},
+					map[string]any{"price": 100.0},
+				}}},
+				{ID: "r2", Data: map[string]any{"items": []any{
+					map[string]any{"price": 4.0, "qty": 4.0},
+				}}},
+				{ID: "r3", Data: map[string]any{}},
+			},
+			valuePath:       "items.*.price",
+			weightPath:      "items.*.qty",
+			expectedSum:     pointer(32.0),
+			expectedAverage: pointer(4.0),
+		},
+		"zero weights": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": 10.0, "qty": 0.0}},
+			},
+			valuePath:   "price",
+			weightPath:  "qty",
+			expectedSum: pointer(0.0),
+		},
+		"negative weight": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": 10.0, "qty": -1.0}},
+			},
+			valuePath:   "price",
+			weightPath:  "qty",
+			expectError: true,
+		},
+		"mismatching paths": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": 10.0, "items": []any{
+					map[string]any{"qty": 3.0},
+					map[string]any{"qty": 1.0},
+				}}},
+			},
+			valuePath:   "price",
+			weightPath:  "items.*.qty",
+			expectError: true,
+		},
+		"non numeric value": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": "not number", "qty": 1.0}},
+			},
+			valuePath:   "price",
+			weightPath:  "qty",
+			expectError: true,
+		},
+		"non numeric weight": {
+			records: []*api.Record{
+				{ID: "r1", Data: map[string]any{"price": 1.0, "qty": "not number"}},
+			},
+			valuePath:   "price",
+			weightPath:  "qty",
+			expectError: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			sum, sumErr := record.WeightedSum(c.records, c.valuePath, c.weightPath)
+			average, averageErr := record.WeightedAverage(c.records, c.valuePath, c.weightPath)
+			if c.expectError {
+				assert.Error(t, sumErr)
+				assert.Error(t, averageErr)
+				return
+			}
+			require.NoError(t, sumErr)
+			require.NoError(t, averageErr)
+			assertFloatPointer(t, c.expectedSum, sum)
+			assertFloatPointer(t, c.expectedAverage, average)
+		})
+	}
+}